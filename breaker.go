@@ -0,0 +1,254 @@
+package leonardo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit in a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request without sending it.
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe requests through to test recovery.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitOpenError is returned, without sending the request, while the
+// circuit of the request's OperationGroup is open.
+type CircuitOpenError struct {
+	Group OperationGroup
+	// RetryAt is when the circuit half-opens to let probe requests through.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s requests until %s", e.Group, e.RetryAt.Format(time.RFC3339))
+}
+
+// CircuitBreaker stops sending requests in an OperationGroup while the API is
+// failing them. A circuit opens once at least MinRequests requests in the last
+// Window have failed at ErrorRate or more; it fails requests fast with a
+// *CircuitOpenError for OpenDuration, then half-opens and lets Probes requests
+// through. It closes if they all succeed and opens again if any fails. Server
+// errors, rate limiting, timeouts and transport errors count as failures;
+// other client errors do not, and requests the caller cancels are not counted.
+// When set on Client.CircuitBreaker it guards every request.
+type CircuitBreaker struct {
+	// ErrorRate is the fraction of failed requests that opens a circuit. Zero uses 0.5.
+	ErrorRate float64
+
+	// MinRequests is the fewest requests in Window that can open a circuit. Zero uses 10.
+	MinRequests int
+
+	// Window is how far back requests are counted. Zero uses one minute.
+	Window time.Duration
+
+	// OpenDuration is how long a circuit stays open. Zero uses 30 seconds.
+	OpenDuration time.Duration
+
+	// Probes is how many requests a half-open circuit lets through. Zero uses 1.
+	Probes int
+
+	// OnStateChange, when set, is called whenever a circuit changes state.
+	OnStateChange func(group OperationGroup, from, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[OperationGroup]*circuit
+}
+
+// circuit is the state of one OperationGroup.
+type circuit struct {
+	state     CircuitState
+	outcomes  []outcome
+	openedAt  time.Time
+	probes    int // probes in flight
+	successes int // probes succeeded
+}
+
+// outcome is a counted request.
+type outcome struct {
+	at     time.Time
+	failed bool
+}
+
+// stateChange is a transition to report once the lock is released.
+type stateChange struct {
+	group    OperationGroup
+	from, to CircuitState
+}
+
+// NewCircuitBreaker creates a CircuitBreaker with the default settings.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{circuits: map[OperationGroup]*circuit{}}
+}
+
+// State returns the state of group's circuit.
+func (b *CircuitBreaker) State(group OperationGroup) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(group)
+	if c.state == CircuitOpen && !time.Now().Before(c.openedAt.Add(b.openDuration())) {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// circuit returns group's circuit. The caller must hold b.mu.
+func (b *CircuitBreaker) circuit(group OperationGroup) *circuit {
+	if b.circuits == nil {
+		b.circuits = map[OperationGroup]*circuit{}
+	}
+	c, ok := b.circuits[group]
+	if !ok {
+		c = &circuit{}
+		b.circuits[group] = c
+	}
+	return c
+}
+
+func (b *CircuitBreaker) openDuration() time.Duration {
+	if b.OpenDuration > 0 {
+		return b.OpenDuration
+	}
+	return 30 * time.Second
+}
+
+// allow admits a request in group, returning the function to call with its
+// result, or a *CircuitOpenError.
+func (b *CircuitBreaker) allow(group OperationGroup) (func(error), error) {
+	b.mu.Lock()
+	c := b.circuit(group)
+	now := time.Now()
+	var changes []stateChange
+
+	if c.state == CircuitOpen {
+		retryAt := c.openedAt.Add(b.openDuration())
+		if now.Before(retryAt) {
+			b.mu.Unlock()
+			return nil, &CircuitOpenError{Group: group, RetryAt: retryAt}
+		}
+		changes = append(changes, b.transition(group, c, CircuitHalfOpen, now))
+	}
+
+	probe := c.state == CircuitHalfOpen
+	if probe {
+		probes := max(b.Probes, 1)
+		if c.probes+c.successes >= probes {
+			b.mu.Unlock()
+			b.notify(changes)
+			return nil, &CircuitOpenError{Group: group, RetryAt: now.Add(b.openDuration())}
+		}
+		c.probes++
+	}
+	b.mu.Unlock()
+	b.notify(changes)
+
+	return func(err error) { b.record(group, probe, err) }, nil
+}
+
+// record counts the result of a request admitted by allow.
+func (b *CircuitBreaker) record(group OperationGroup, probe bool, err error) {
+	b.mu.Lock()
+	c := b.circuit(group)
+	now := time.Now()
+	var changes []stateChange
+
+	if probe {
+		c.probes--
+	}
+	if ClassifyError(err) == ErrorClassCanceled {
+		b.mu.Unlock()
+		return
+	}
+	failed := countsAsFailure(err)
+
+	switch {
+	case probe && c.state == CircuitHalfOpen && failed:
+		changes = append(changes, b.transition(group, c, CircuitOpen, now))
+	case probe && c.state == CircuitHalfOpen:
+		c.successes++
+		if c.successes >= max(b.Probes, 1) {
+			changes = append(changes, b.transition(group, c, CircuitClosed, now))
+		}
+	case c.state == CircuitClosed:
+		window := b.Window
+		if window <= 0 {
+			window = time.Minute
+		}
+		c.outcomes = append(c.outcomes, outcome{at: now, failed: failed})
+		i := 0
+		for i < len(c.outcomes) && now.Sub(c.outcomes[i].at) > window {
+			i++
+		}
+		c.outcomes = c.outcomes[i:]
+
+		failures := 0
+		for _, o := range c.outcomes {
+			if o.failed {
+				failures++
+			}
+		}
+		minRequests, rate := b.MinRequests, b.ErrorRate
+		if minRequests <= 0 {
+			minRequests = 10
+		}
+		if rate <= 0 {
+			rate = 0.5
+		}
+		if len(c.outcomes) >= minRequests && float64(failures)/float64(len(c.outcomes)) >= rate {
+			changes = append(changes, b.transition(group, c, CircuitOpen, now))
+		}
+	}
+	b.mu.Unlock()
+	b.notify(changes)
+}
+
+// transition moves c to state. The caller must hold b.mu.
+func (b *CircuitBreaker) transition(group OperationGroup, c *circuit, state CircuitState, now time.Time) stateChange {
+	change := stateChange{group: group, from: c.state, to: state}
+	c.state = state
+	c.successes = 0
+	switch state {
+	case CircuitOpen:
+		c.openedAt = now
+	case CircuitClosed:
+		c.outcomes = nil
+	}
+	return change
+}
+
+// notify reports state changes to OnStateChange.
+func (b *CircuitBreaker) notify(changes []stateChange) {
+	if b.OnStateChange == nil {
+		return
+	}
+	for _, c := range changes {
+		b.OnStateChange(c.group, c.from, c.to)
+	}
+}
+
+// countsAsFailure reports whether err suggests the API is degraded.
+func countsAsFailure(err error) bool {
+	switch ClassifyError(err) {
+	case ErrorClassServer, ErrorClassRateLimited, ErrorClassTimeout, ErrorClassTransport:
+		return true
+	}
+	return false
+}
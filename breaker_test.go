@@ -0,0 +1,148 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreaker tests opening, failing fast, half-opening and closing.
+func TestCircuitBreaker(t *testing.T) {
+	failing := true
+	requests := 0
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/generations/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(APIErrorResponse{Message: "Generation not found."})
+			return
+		}
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(APIErrorResponse{Message: "Service unavailable."})
+			return
+		}
+		json.NewEncoder(w).Encode(GetGenerationResponse{})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	var changes []string
+	breaker := NewCircuitBreaker()
+	breaker.MinRequests = 4
+	breaker.OpenDuration = 20 * time.Millisecond
+	breaker.OnStateChange = func(group OperationGroup, from, to CircuitState) {
+		changes = append(changes, string(group)+": "+from.String()+" -> "+to.String())
+	}
+	client.CircuitBreaker = breaker
+
+	ctx := context.Background()
+	// Client errors don't count as failures.
+	for i := 0; i < 4; i++ {
+		client.Images.GetImageGeneration(ctx, "missing")
+	}
+	if state := breaker.State(GroupPolling); state != CircuitClosed {
+		t.Fatalf("Expected the circuit to stay closed, got %s", state)
+	}
+
+	for i := 0; i < 4; i++ {
+		client.Images.GetImageGeneration(ctx, "gen-1")
+	}
+	if state := breaker.State(GroupPolling); state != CircuitOpen {
+		t.Fatalf("Expected the circuit to open, got %s", state)
+	}
+
+	sent := requests
+	_, err := client.Images.GetImageGeneration(ctx, "gen-1")
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || openErr.Group != GroupPolling {
+		t.Fatalf("Expected a CircuitOpenError, got %v", err)
+	}
+	if requests != sent {
+		t.Error("Expected the request not to be sent while the circuit is open")
+	}
+	if breaker.State(GroupGeneration) != CircuitClosed {
+		t.Error("Expected other groups to be unaffected")
+	}
+
+	// A failed probe opens the circuit again.
+	time.Sleep(25 * time.Millisecond)
+	if state := breaker.State(GroupPolling); state != CircuitHalfOpen {
+		t.Fatalf("Expected the circuit to half-open, got %s", state)
+	}
+	client.Images.GetImageGeneration(ctx, "gen-1")
+	if state := breaker.State(GroupPolling); state != CircuitOpen {
+		t.Fatalf("Expected the failed probe to open the circuit, got %s", state)
+	}
+
+	// A successful probe closes it.
+	failing = false
+	time.Sleep(25 * time.Millisecond)
+	if _, err := client.Images.GetImageGeneration(ctx, "gen-1"); err != nil {
+		t.Fatalf("Expected the probe to succeed, got %v", err)
+	}
+	if state := breaker.State(GroupPolling); state != CircuitClosed {
+		t.Fatalf("Expected the circuit to close, got %s", state)
+	}
+
+	want := []string{
+		"polling: closed -> open",
+		"polling: open -> half-open",
+		"polling: half-open -> open",
+		"polling: open -> half-open",
+		"polling: half-open -> closed",
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected state changes %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("State change %d = %q, want %q", i, changes[i], want[i])
+		}
+	}
+}
+
+// TestCircuitBreakerProbes tests limiting the requests a half-open circuit lets
+// through, and releasing the slot of a canceled probe.
+func TestCircuitBreakerProbes(t *testing.T) {
+	breaker := NewCircuitBreaker()
+	breaker.MinRequests = 1
+	breaker.OpenDuration = time.Millisecond
+
+	done, err := breaker.allow(GroupUpload)
+	if err != nil {
+		t.Fatalf("allow failed: %v", err)
+	}
+	done(&APIError{StatusCode: http.StatusBadGateway})
+	time.Sleep(2 * time.Millisecond)
+
+	probe, err := breaker.allow(GroupUpload)
+	if err != nil {
+		t.Fatalf("Expected a probe to be allowed, got %v", err)
+	}
+	if _, err := breaker.allow(GroupUpload); err == nil {
+		t.Error("Expected a second concurrent probe to be refused")
+	}
+	probe(context.Canceled)
+	if state := breaker.State(GroupUpload); state != CircuitHalfOpen {
+		t.Errorf("Expected a canceled probe not to be counted, got %s", state)
+	}
+	if _, err := breaker.allow(GroupUpload); err != nil {
+		t.Errorf("Expected the released probe slot to be reused, got %v", err)
+	}
+}
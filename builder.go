@@ -0,0 +1,222 @@
+package leonardo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GenerationBuilder builds a CreateGenerationRequest without juggling pointers.
+// Each mode method sets the fields that mode needs, and Build validates the
+// result. Errors from individual methods are collected and reported by Build.
+//
+//	req, err := leonardo.NewGeneration("A lighthouse at dusk.").
+//		Model(phoenix).
+//		Aspect(16.0 / 9).
+//		Ultra().
+//		Build()
+type GenerationBuilder struct {
+	req    CreateGenerationRequest
+	model  *ModelInfo
+	aspect float64
+	pixels int
+	errs   []error
+}
+
+// NewGeneration starts building a request for prompt.
+func NewGeneration(prompt string) *GenerationBuilder {
+	return &GenerationBuilder{req: CreateGenerationRequest{Prompt: prompt}}
+}
+
+// Model generates with model. Its default size is used unless Size or Aspect is set.
+func (b *GenerationBuilder) Model(model ModelInfo) *GenerationBuilder {
+	b.model = &model
+	b.req.ModelID = Ptr(model.ID)
+	return b
+}
+
+// ModelName generates with the known model named name; see ModelByName.
+func (b *GenerationBuilder) ModelName(name string) *GenerationBuilder {
+	model, ok := ModelByName(name)
+	if !ok {
+		b.errs = append(b.errs, fmt.Errorf("model %q not found", name))
+		return b
+	}
+	return b.Model(model)
+}
+
+// NegativePrompt sets what the images should not contain.
+func (b *GenerationBuilder) NegativePrompt(prompt string) *GenerationBuilder {
+	b.req.NegativePrompt = Ptr(prompt)
+	return b
+}
+
+// Size sets the width and height to generate at.
+func (b *GenerationBuilder) Size(width, height int) *GenerationBuilder {
+	b.req.Width, b.req.Height = Ptr(width), Ptr(height)
+	b.aspect, b.pixels = 0, 0
+	return b
+}
+
+// Aspect plans the width and height for aspect ratio (width divided by height)
+// at the model's default pixel count; see PlanDimensions.
+func (b *GenerationBuilder) Aspect(ratio float64) *GenerationBuilder {
+	b.aspect = ratio
+	b.req.Width, b.req.Height = nil, nil
+	return b
+}
+
+// Pixels plans the width and height for a target pixel count; see PlanDimensions.
+func (b *GenerationBuilder) Pixels(pixels int) *GenerationBuilder {
+	b.pixels = pixels
+	b.req.Width, b.req.Height = nil, nil
+	return b
+}
+
+// NumImages sets the number of images to generate.
+func (b *GenerationBuilder) NumImages(n int) *GenerationBuilder {
+	b.req.NumImages = Ptr(n)
+	return b
+}
+
+// Seed sets the seed, for reproducible results.
+func (b *GenerationBuilder) Seed(seed int) *GenerationBuilder {
+	b.req.Seed = Ptr(seed)
+	return b
+}
+
+// GuidanceScale sets how strongly the images follow the prompt.
+func (b *GenerationBuilder) GuidanceScale(scale int) *GenerationBuilder {
+	b.req.GuidanceScale = Ptr(scale)
+	return b
+}
+
+// Steps sets the number of inference steps.
+func (b *GenerationBuilder) Steps(steps int) *GenerationBuilder {
+	b.req.NumInferenceSteps = Ptr(steps)
+	return b
+}
+
+// Scheduler sets the scheduler used to sample the images.
+func (b *GenerationBuilder) Scheduler(scheduler Scheduler) *GenerationBuilder {
+	b.req.Scheduler = Ptr(scheduler)
+	return b
+}
+
+// PresetStyle sets the preset style.
+func (b *GenerationBuilder) PresetStyle(style PresetStyle) *GenerationBuilder {
+	b.req.PresetStyle = Ptr(style)
+	return b
+}
+
+// Transparency sets whether the images have a transparent background.
+func (b *GenerationBuilder) Transparency(transparency Transparency) *GenerationBuilder {
+	b.req.Transparency = Ptr(transparency)
+	return b
+}
+
+// Public sets whether the images are shown in the community feed.
+func (b *GenerationBuilder) Public(public bool) *GenerationBuilder {
+	b.req.Public = Ptr(public)
+	return b
+}
+
+// InitImage generates from an uploaded init image with the given strength.
+func (b *GenerationBuilder) InitImage(initImageID string, strength float64) *GenerationBuilder {
+	b.req.InitImageID = Ptr(initImageID)
+	b.req.InitStrength = Ptr(strength)
+	return b
+}
+
+// Alchemy enables Alchemy.
+func (b *GenerationBuilder) Alchemy() *GenerationBuilder {
+	b.req.Alchemy = Ptr(true)
+	return b
+}
+
+// PhotoReal enables PhotoReal at version with strength (0.55 low, 0.5 medium,
+// 0.45 high). PhotoReal requires Alchemy, which is enabled too. Version v2 also
+// requires a model that supports it.
+func (b *GenerationBuilder) PhotoReal(version PhotoRealVersion, strength float64) *GenerationBuilder {
+	b.req.Alchemy = Ptr(true)
+	b.req.PhotoReal = Ptr(true)
+	b.req.PhotoRealVersion = Ptr(version)
+	b.req.PhotoRealStrength = Ptr(strength)
+	return b
+}
+
+// Ultra enables Ultra mode, which requires Alchemy to be disabled.
+func (b *GenerationBuilder) Ultra() *GenerationBuilder {
+	b.req.Alchemy = Ptr(false)
+	b.req.Ultra = Ptr(true)
+	return b
+}
+
+// HighResolution enables High Resolution.
+func (b *GenerationBuilder) HighResolution() *GenerationBuilder {
+	b.req.HighResolution = Ptr(true)
+	return b
+}
+
+// Unzoom zooms out of the init image by amount.
+func (b *GenerationBuilder) Unzoom(initImageID string, amount int) *GenerationBuilder {
+	b.req.Unzoom = Ptr(true)
+	b.req.UnzoomAmount = Ptr(amount)
+	b.req.InitImageID = Ptr(initImageID)
+	return b
+}
+
+// Canvas makes a canvas request of type requestType. maskID may be empty unless
+// requestType is CanvasRequestTypeInpaint.
+func (b *GenerationBuilder) Canvas(requestType CanvasRequestType, initID, maskID string) *GenerationBuilder {
+	b.req.CanvasRequest = Ptr(true)
+	b.req.CanvasRequestType = Ptr(requestType)
+	b.req.CanvasInitID = Ptr(initID)
+	if maskID != "" {
+		b.req.CanvasMaskID = Ptr(maskID)
+	}
+	return b
+}
+
+// Tiling generates images that tile seamlessly.
+func (b *GenerationBuilder) Tiling() *GenerationBuilder {
+	b.req.Tiling = Ptr(true)
+	return b
+}
+
+// Build returns the request, or every problem found with it joined with
+// errors.Join. The request is validated against the model passed to Model,
+// if any, so models missing from KnownModels are checked by their capabilities.
+func (b *GenerationBuilder) Build() (CreateGenerationRequest, error) {
+	req := b.req
+	errs := append([]error(nil), b.errs...)
+
+	model := req.model()
+	_, known := ModelByID(model.ID)
+	if b.model != nil {
+		model, known = *b.model, true
+	}
+
+	if req.Width == nil && req.Height == nil {
+		dims, err := PlanDimensions(DimensionRequest{
+			AspectRatio:    b.aspect,
+			Pixels:         b.pixels,
+			Model:          model,
+			HighResolution: deref(req.HighResolution),
+			Ultra:          deref(req.Ultra),
+			UpscaleRatio:   deref(req.UpscaleRatio),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("planning dimensions failed: %w", err))
+		} else {
+			req.Width, req.Height = Ptr(dims.Width), Ptr(dims.Height)
+		}
+	}
+
+	if err := req.validate(model, known); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return CreateGenerationRequest{}, err
+	}
+	return req, nil
+}
@@ -0,0 +1,102 @@
+package leonardo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerationBuilder tests building requests in each mode.
+func TestGenerationBuilder(t *testing.T) {
+	req, err := NewGeneration("A lighthouse at dusk.").
+		ModelName("Leonardo Phoenix").
+		Aspect(16.0 / 9).
+		Ultra().
+		Seed(42).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if *req.ModelID != "de7d3faf-762f-48e0-b3b7-9d0ac3a3fcf3" || !*req.Ultra || *req.Alchemy {
+		t.Errorf("Unexpected ultra request: %+v", req)
+	}
+	if *req.Width != 1472 || *req.Height != 832 || *req.Seed != 42 {
+		t.Errorf("Expected 1472x832 with seed 42, got %dx%d seed %d", *req.Width, *req.Height, *req.Seed)
+	}
+
+	req, err = NewGeneration("A portrait in soft light.").
+		ModelName("Leonardo Kino XL").
+		PhotoReal(PhotoRealVersionV2, 0.5).
+		PresetStyle(PresetStyleCinematic).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if !*req.PhotoReal || !*req.Alchemy || *req.PhotoRealVersion != PhotoRealVersionV2 || *req.Width != 1024 || *req.Height != 768 {
+		t.Errorf("Unexpected PhotoReal request: %+v", req)
+	}
+
+	req, err = NewGeneration("A wider view.").Unzoom("init-1", 1).Build()
+	if err != nil || !*req.Unzoom || *req.InitImageID != "init-1" {
+		t.Errorf("Unexpected unzoom request: %+v, %v", req, err)
+	}
+
+	req, err = NewGeneration("Replace the sky.").Canvas(CanvasRequestTypeInpaint, "init-1", "mask-1").Size(512, 512).Build()
+	if err != nil || *req.CanvasMaskID != "mask-1" || *req.Width != 512 {
+		t.Errorf("Unexpected canvas request: %+v, %v", req, err)
+	}
+
+	req, err = NewGeneration("Cobblestones.").Tiling().Build()
+	if err != nil || !*req.Tiling {
+		t.Errorf("Unexpected tiling request: %+v, %v", req, err)
+	}
+}
+
+// TestGenerationBuilderErrors tests that Build reports every problem at once.
+func TestGenerationBuilderErrors(t *testing.T) {
+	_, err := NewGeneration("").
+		ModelName("Leonardo Kino XL").
+		Ultra().
+		PhotoReal(PhotoRealVersionV2, 0.6).
+		Canvas(CanvasRequestTypeInpaint, "init-1", "").
+		Size(2000, 512).
+		Build()
+	if err == nil {
+		t.Fatal("Expected errors, got nil")
+	}
+	for _, want := range []string{
+		"prompt is required",
+		"width 2000 must be between 32 and 1024",
+		"does not support ultra",
+		"ultra requires alchemy to be disabled",
+		"photoRealStrength 0.6 must be 0.45, 0.5 or 0.55",
+		"inpainting requires canvasMaskId",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got:\n%v", want, err)
+		}
+	}
+
+	if _, err := NewGeneration("A lighthouse.").ModelName("Nonexistent Model").Build(); err == nil || !strings.Contains(err.Error(), `model "Nonexistent Model" not found`) {
+		t.Errorf("Expected unknown model error, got %v", err)
+	}
+}
+
+// TestGenerationBuilderCatalogModel tests building for a model missing from KnownModels.
+func TestGenerationBuilderCatalogModel(t *testing.T) {
+	model := ModelInfo{
+		ID: "platform-phoenix", Name: "Platform Phoenix", SDVersion: SDVersionPhoenix,
+		DefaultWidth: 1472, DefaultHeight: 832, MaxDimension: 1536, Ultra: true,
+	}
+	req, err := NewGeneration("A lighthouse at dusk.").Model(model).Ultra().Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if *req.Width != 1472 || *req.Height != 832 {
+		t.Errorf("Expected 1472x832, got %dx%d", *req.Width, *req.Height)
+	}
+
+	model.Ultra = false
+	if _, err := NewGeneration("A lighthouse at dusk.").Model(model).Ultra().Build(); err == nil || !strings.Contains(err.Error(), "planning dimensions failed") {
+		t.Errorf("Expected the planning error to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package leonardo
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStore holds cached responses for a ResponseCache.
+type CacheStore interface {
+	// Get returns the value stored under key, if present and not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for ttl.
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete removes key.
+	Delete(key string)
+}
+
+// LRUCache is an in-memory CacheStore that holds a fixed number of entries,
+// evicting the least recently used first.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// lruEntry is an entry in an LRUCache.
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache creates an LRUCache holding at most size entries.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{size: size, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// Get implements CacheStore.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.value, true
+}
+
+// Set implements CacheStore.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &lruEntry{key: key, value: value, expires: time.Now().Add(ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Delete implements CacheStore.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of entries held, including expired ones not yet evicted.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// cacheableOperations are the calls whose responses a ResponseCache stores.
+var cacheableOperations = map[string]bool{
+	"Elements.ListElements":            true,
+	"Models.ListPlatformModels":        true,
+	"Models.GetCustomModel":            true,
+	"Images.GetImageGeneration":        true,
+	"ThreeDModelAssets.Get3DModelByID": true,
+}
+
+// cacheInvalidations maps the calls that change a resource to the cached call
+// that reads it. Both address the resource by the same ID.
+var cacheInvalidations = map[string]string{
+	"Images.DeleteGeneration":         "Images.GetImageGeneration",
+	"Models.DeleteCustomModel":        "Models.GetCustomModel",
+	"Models.UpdateCustomModel":        "Models.GetCustomModel",
+	"ThreeDModelAssets.Delete3DModel": "ThreeDModelAssets.Get3DModelByID",
+}
+
+// DefaultCacheTTL is how long a ResponseCache keeps responses when no TTL is set.
+const DefaultCacheTTL = 10 * time.Minute
+
+// ResponseCache caches the responses of calls that rarely or never change:
+// the element and platform model lists, custom models, 3D models, and
+// generations that have finished. Generations still PENDING, and custom
+// models still training, are never cached. Deleting or updating a resource
+// through the client evicts its cached response. Install it with
+//
+//	cache := leonardo.NewResponseCache(leonardo.NewLRUCache(1000))
+//	client.Use(cache.Middleware())
+//
+// Cache keys hold no API key or account, so responses cached for one account
+// are served to every call through the cache. When calls are made for several
+// accounts, such as through a KeyPool holding keys of different accounts, set
+// Scope or use a cache per account.
+type ResponseCache struct {
+	// Store holds the cached responses.
+	Store CacheStore
+
+	// Scope, when set, returns the account a call is made for, such as a
+	// tenant name. Responses are cached separately for each scope; the scope
+	// is hashed before it is used in a key, so it may be an API key.
+	Scope func(ctx context.Context, call *Call) string
+
+	// TTL is how long responses are kept. Zero uses DefaultCacheTTL.
+	TTL time.Duration
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+}
+
+// CacheStats reports the effectiveness of a ResponseCache.
+type CacheStats struct {
+	Hits          int64
+	Misses        int64
+	Invalidations int64
+}
+
+// HitRate returns the fraction of cacheable calls served from the cache.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// NewResponseCache creates a ResponseCache backed by store.
+func NewResponseCache(store CacheStore) *ResponseCache {
+	return &ResponseCache{Store: store}
+}
+
+// Stats returns the hits, misses and invalidations so far.
+func (c *ResponseCache) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Invalidations: c.invalidations.Load()}
+}
+
+// cacheKey returns the key a call's response is cached under.
+func cacheKey(scope, name, id, path string) string {
+	key := name + ":" + path
+	if id != "" {
+		key = name + ":" + id
+	}
+	if scope == "" {
+		return key
+	}
+	sum := sha256.Sum256([]byte(scope))
+	return hex.EncodeToString(sum[:8]) + ":" + key
+}
+
+// Middleware returns the middleware that serves and stores cached responses.
+func (c *ResponseCache) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			name := call.Name()
+			scope := ""
+			if c.Scope != nil {
+				scope = c.Scope(ctx, call)
+			}
+			if !cacheableOperations[name] {
+				err := next(ctx, call)
+				if reads, ok := cacheInvalidations[name]; ok && err == nil {
+					c.Store.Delete(cacheKey(scope, reads, call.ID, ""))
+					c.invalidations.Add(1)
+				}
+				return err
+			}
+
+			key := cacheKey(scope, name, call.ID, call.Path)
+			if data, ok := c.Store.Get(key); ok && json.Unmarshal(data, call.Response) == nil {
+				c.hits.Add(1)
+				return nil
+			}
+			c.misses.Add(1)
+
+			if err := next(ctx, call); err != nil {
+				return err
+			}
+			if _, status, ok := responseJobStatus(call.Response); ok && status != GenerationStatusComplete && status != GenerationStatusFailed {
+				return nil
+			}
+			if data, err := json.Marshal(call.Response); err == nil {
+				ttl := c.TTL
+				if ttl <= 0 {
+					ttl = DefaultCacheTTL
+				}
+				c.Store.Set(key, data, ttl)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestResponseCache tests caching, skipping PENDING generations and invalidation.
+func TestResponseCache(t *testing.T) {
+	requests := map[string]int{}
+	status := GenerationStatusPending
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		switch r.Method + " " + r.URL.Path {
+		case "GET /elements":
+			json.NewEncoder(w).Encode(ListElementsResponse{Loras: []Lora{{AKUUID: Ptr("element-1")}}})
+		case "GET /generations/gen-1":
+			json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{
+				ID:        Ptr("gen-1"),
+				Status:    &status,
+				CreatedAt: &Time{Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+			}})
+		case "DELETE /generations/gen-1":
+			json.NewEncoder(w).Encode(DeleteGenerationResponse{DeleteGenerationsByPK: ResourceID{ID: Ptr("gen-1")}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+	client.Elements = client.NewElementsService()
+
+	cache := NewResponseCache(NewLRUCache(10))
+	client.Use(cache.Middleware())
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Elements.ListElements(ctx)
+		if err != nil {
+			t.Fatalf("ListElements failed: %v", err)
+		}
+		if len(resp.Loras) != 1 || *resp.Loras[0].AKUUID != "element-1" {
+			t.Errorf("Unexpected response: %+v", resp)
+		}
+	}
+	if requests["GET /elements"] != 1 {
+		t.Errorf("Expected 1 request for elements, got %d", requests["GET /elements"])
+	}
+
+	// PENDING generations are fetched every time.
+	for i := 0; i < 2; i++ {
+		client.Images.GetImageGeneration(ctx, "gen-1")
+	}
+	status = GenerationStatusComplete
+	for i := 0; i < 2; i++ {
+		resp, err := client.Images.GetImageGeneration(ctx, "gen-1")
+		if err != nil {
+			t.Fatalf("GetImageGeneration failed: %v", err)
+		}
+		if resp.Status() != GenerationStatusComplete || resp.GenerationsByPK.CreatedAt.Time.Hour() != 12 {
+			t.Errorf("Unexpected response: %+v", resp.GenerationsByPK)
+		}
+	}
+	if requests["GET /generations/gen-1"] != 3 {
+		t.Errorf("Expected 3 requests for the generation, got %d", requests["GET /generations/gen-1"])
+	}
+
+	if _, err := client.Images.DeleteGeneration(ctx, "gen-1"); err != nil {
+		t.Fatalf("DeleteGeneration failed: %v", err)
+	}
+	client.Images.GetImageGeneration(ctx, "gen-1")
+	if requests["GET /generations/gen-1"] != 4 {
+		t.Errorf("Expected the deletion to invalidate the generation, got %d requests", requests["GET /generations/gen-1"])
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 3 || stats.Misses != 5 || stats.Invalidations != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if rate := stats.HitRate(); rate != 0.375 {
+		t.Errorf("Expected a hit rate of 3/8, got %v", rate)
+	}
+}
+
+// TestLRUCache tests eviction by recency and expiry.
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+	cache.Get("a")
+	cache.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if v, ok := cache.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("Expected a to be kept, got %q", v)
+	}
+
+	cache.Set("d", []byte("4"), -time.Second)
+	if _, ok := cache.Get("d"); ok {
+		t.Error("Expected the expired entry to be missing")
+	}
+	cache.Delete("a")
+	if cache.Len() != 0 {
+		t.Errorf("Expected no entries, got %d", cache.Len())
+	}
+}
+
+// accountKey is the context key of the account in TestResponseCacheScope.
+type accountKey struct{}
+
+// TestResponseCacheScope tests that responses are cached separately per scope.
+func TestResponseCacheScope(t *testing.T) {
+	requests := 0
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/elements" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		requests++
+		json.NewEncoder(w).Encode(ListElementsResponse{})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Elements = client.NewElementsService()
+
+	store := NewLRUCache(10)
+	cache := NewResponseCache(store)
+	cache.Scope = func(ctx context.Context, call *Call) string {
+		account, _ := ctx.Value(accountKey{}).(string)
+		return account
+	}
+	client.Use(cache.Middleware())
+
+	for _, account := range []string{"account-a", "account-b", "account-a"} {
+		ctx := context.WithValue(context.Background(), accountKey{}, account)
+		if _, err := client.Elements.ListElements(ctx); err != nil {
+			t.Fatalf("ListElements failed: %v", err)
+		}
+	}
+	if requests != 2 || store.Len() != 2 {
+		t.Errorf("Expected one request and entry per account, got %d requests and %d entries", requests, store.Len())
+	}
+}
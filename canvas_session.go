@@ -0,0 +1,380 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CanvasMode selects the realtime canvas endpoint a CanvasSession calls.
+type CanvasMode int
+
+const (
+	// CanvasGenerate calls CreateLCMGeneration.
+	CanvasGenerate CanvasMode = iota
+	// CanvasRefine calls PerformInstantRefine.
+	CanvasRefine
+	// CanvasInpaint calls PerformInpainting with the frame's mask.
+	CanvasInpaint
+	// CanvasUpscale calls PerformAlchemyUpscale.
+	CanvasUpscale
+)
+
+// CanvasSettings are the generation settings a CanvasSession sends with every frame.
+type CanvasSettings struct {
+	Mode           CanvasMode
+	Prompt         string
+	Style          *string
+	Seed           *int
+	Strength       *float64
+	Guidance       *float64
+	Steps          *int
+	Width          *int
+	Height         *int
+	RefineCreative *bool
+	RefineStrength *float64
+
+	// Debounce is how long frames are coalesced. Zero uses DefaultCanvasDebounce.
+	Debounce time.Duration
+
+	// MaxInFlight is how many requests may be outstanding at once. Zero uses 2.
+	// Changing it after the session has started has no effect.
+	MaxInFlight int
+}
+
+// CanvasResult is the outcome of a frame sent by a CanvasSession.
+type CanvasResult struct {
+	// Images are the decoded result images.
+	Images []image.Image
+	// RequestTimestamp identifies the frame the result is for.
+	RequestTimestamp string
+	// APICreditCost is the cost reported for the frame.
+	APICreditCost int
+	// Err is set if the request failed.
+	Err error
+}
+
+// DefaultCanvasDebounce is how long a CanvasSession coalesces frames when no debounce is set.
+const DefaultCanvasDebounce = 150 * time.Millisecond
+
+// CanvasSession streams sketch frames to the realtime canvas. Frames arriving
+// within CanvasSettings.Debounce of the first unsent frame are coalesced and
+// only the newest is sent, so a drawing tool can send a frame on every stroke.
+// Each request carries a RequestTimestamp; a response older than one already
+// delivered is discarded, so results never go backwards. Results are delivered
+// on Results, which holds only the newest undelivered result.
+type CanvasSession struct {
+	service *RealtimeCanvasService
+
+	mu        sync.Mutex
+	settings  CanvasSettings
+	pending   *canvasFrame
+	lastStamp int64
+	delivered int64
+
+	signal  chan struct{}
+	results chan CanvasResult
+	cancel  context.CancelFunc
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+// canvasFrame is a frame waiting to be sent.
+type canvasFrame struct {
+	image image.Image
+	mask  image.Image
+}
+
+// NewSession starts a CanvasSession with the given settings. It runs until ctx
+// is done or Close is called.
+func (s *RealtimeCanvasService) NewSession(ctx context.Context, settings CanvasSettings) *CanvasSession {
+	ctx, cancel := context.WithCancel(ctx)
+	session := &CanvasSession{
+		service:  s,
+		settings: settings,
+		signal:   make(chan struct{}, 1),
+		results:  make(chan CanvasResult, 1),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go session.run(ctx)
+	return session
+}
+
+// Settings returns the current settings.
+func (cs *CanvasSession) Settings() CanvasSettings {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.settings
+}
+
+// SetSettings replaces the settings used for frames sent from now on.
+func (cs *CanvasSession) SetSettings(settings CanvasSettings) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.settings = settings
+}
+
+// Send queues a frame, replacing any frame not yet sent. It never blocks.
+func (cs *CanvasSession) Send(img image.Image) {
+	cs.SendMasked(img, nil)
+}
+
+// SendMasked queues a frame with an inpainting mask, replacing any frame not yet sent.
+func (cs *CanvasSession) SendMasked(img, mask image.Image) {
+	cs.mu.Lock()
+	if cs.pending != nil {
+		cs.dropped.Add(1)
+	}
+	cs.pending = &canvasFrame{image: img, mask: mask}
+	cs.mu.Unlock()
+
+	select {
+	case cs.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Results returns the channel results are delivered on. It is closed once the
+// session has stopped.
+func (cs *CanvasSession) Results() <-chan CanvasResult {
+	return cs.results
+}
+
+// Dropped returns the number of frames coalesced away and results discarded as stale.
+func (cs *CanvasSession) Dropped() int64 {
+	return cs.dropped.Load()
+}
+
+// Close stops the session, waits for outstanding requests, and closes Results.
+func (cs *CanvasSession) Close() {
+	cs.once.Do(cs.cancel)
+	<-cs.done
+}
+
+// run coalesces frames and sends them until ctx is done.
+func (cs *CanvasSession) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(cs.results)
+		close(cs.done)
+	}()
+
+	limit := cs.Settings().MaxInFlight
+	if limit <= 0 {
+		limit = 2
+	}
+	inFlight := make(chan struct{}, limit)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cs.signal:
+		}
+
+		debounce := cs.Settings().Debounce
+		if debounce <= 0 {
+			debounce = DefaultCanvasDebounce
+		}
+		timer := time.NewTimer(debounce)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case inFlight <- struct{}{}:
+		}
+
+		cs.mu.Lock()
+		frame, settings := cs.pending, cs.settings
+		cs.pending = nil
+		stamp := max(time.Now().UnixMilli(), cs.lastStamp+1)
+		cs.lastStamp = stamp
+		cs.mu.Unlock()
+		if frame == nil {
+			<-inFlight
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+			cs.deliver(stamp, cs.send(ctx, frame, settings, strconv.FormatInt(stamp, 10)))
+		}()
+	}
+}
+
+// send encodes frame and calls the endpoint selected by settings.Mode.
+func (cs *CanvasSession) send(ctx context.Context, frame *canvasFrame, settings CanvasSettings, stamp string) CanvasResult {
+	result := CanvasResult{RequestTimestamp: stamp}
+	imageURL, err := EncodeDataURL(frame.image, "image/jpeg")
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	var job *LCMGenerationJob
+	switch settings.Mode {
+	case CanvasRefine:
+		var resp *PerformInstantRefineResponse
+		resp, err = cs.service.PerformInstantRefine(ctx, PerformInstantRefineRequest{
+			Guidance: settings.Guidance, Height: settings.Height, ImageDataURL: imageURL, Prompt: settings.Prompt,
+			RefineCreative: settings.RefineCreative, RefineStrength: settings.RefineStrength, RequestTimestamp: &stamp,
+			Seed: settings.Seed, Steps: settings.Steps, Strength: settings.Strength, Style: settings.Style, Width: settings.Width,
+		})
+		if resp != nil {
+			job = resp.LCMGenerationJob
+		}
+	case CanvasInpaint:
+		if frame.mask == nil {
+			err = errors.New("inpainting frame has no mask")
+			break
+		}
+		var maskURL string
+		if maskURL, err = EncodeDataURL(frame.mask, "image/png"); err != nil {
+			break
+		}
+		var resp *PerformInpaintingResponse
+		resp, err = cs.service.PerformInpainting(ctx, PerformInpaintingRequest{
+			Guidance: settings.Guidance, Height: settings.Height, ImageDataURL: imageURL, MaskDataURL: maskURL,
+			Prompt: settings.Prompt, RequestTimestamp: &stamp, Seed: settings.Seed, Steps: settings.Steps,
+			Strength: settings.Strength, Style: settings.Style, Width: settings.Width,
+		})
+		if resp != nil {
+			job = resp.LCMGenerationJob
+		}
+	case CanvasUpscale:
+		var resp *PerformAlchemyUpscaleResponse
+		resp, err = cs.service.PerformAlchemyUpscale(ctx, PerformAlchemyUpscaleRequest{
+			Guidance: settings.Guidance, Height: settings.Height, ImageDataURL: imageURL, Prompt: settings.Prompt,
+			RequestTimestamp: &stamp, Seed: settings.Seed, Steps: settings.Steps, Strength: settings.Strength,
+			Style: settings.Style, Width: settings.Width,
+		})
+		if resp != nil && resp.LCMGenerationJob != nil {
+			up := resp.LCMGenerationJob
+			job = &LCMGenerationJob{APICreditCost: up.APICreditCost, ImageDataURL: up.ImageDataURL, RequestTimestamp: up.RequestTimestamp}
+		}
+	default:
+		var resp *CreateLCMGenerationResponse
+		resp, err = cs.service.CreateLCMGeneration(ctx, CreateLCMGenerationRequest{
+			Guidance: settings.Guidance, Height: settings.Height, ImageDataURL: imageURL, Prompt: settings.Prompt,
+			RefineCreative: settings.RefineCreative, RefineStrength: settings.RefineStrength, RequestTimestamp: &stamp,
+			Seed: settings.Seed, Steps: settings.Steps, Strength: settings.Strength, Style: settings.Style, Width: settings.Width,
+		})
+		if resp != nil {
+			job = resp.LCMGenerationJob
+		}
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if job == nil {
+		result.Err = errors.New("realtime canvas response has no job")
+		return result
+	}
+
+	if job.RequestTimestamp != nil && *job.RequestTimestamp != "" {
+		result.RequestTimestamp = *job.RequestTimestamp
+	}
+	result.APICreditCost = deref(job.APICreditCost)
+	for _, u := range job.ImageDataURL {
+		img, err := DecodeDataURL(u)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		result.Images = append(result.Images, img)
+	}
+	return result
+}
+
+// deliver passes result on unless a newer one has been delivered, replacing
+// any result not yet received.
+func (cs *CanvasSession) deliver(sent int64, result CanvasResult) {
+	stamp := sent
+	if n, err := strconv.ParseInt(result.RequestTimestamp, 10, 64); err == nil {
+		stamp = n
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if stamp <= cs.delivered {
+		cs.dropped.Add(1)
+		return
+	}
+	if result.Err == nil {
+		cs.delivered = stamp
+	}
+
+	for {
+		select {
+		case cs.results <- result:
+			return
+		default:
+		}
+		select {
+		case <-cs.results:
+			cs.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// EncodeDataURL encodes img as a base64 data URL of the given media type,
+// "image/jpeg" or "image/png".
+func EncodeDataURL(img image.Image, mediaType string) (string, error) {
+	var buf bytes.Buffer
+	var err error
+	switch mediaType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	case "image/png":
+		err = png.Encode(&buf, img)
+	default:
+		return "", fmt.Errorf("encoding data URL failed: unsupported media type %q", mediaType)
+	}
+	if err != nil {
+		return "", fmt.Errorf("encoding data URL failed: %w", err)
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeDataURL decodes an image from a base64 data URL.
+func DecodeDataURL(dataURL string) (image.Image, error) {
+	rest, ok := strings.CutPrefix(dataURL, "data:")
+	if !ok {
+		return nil, errors.New("decoding data URL failed: missing data: prefix")
+	}
+	_, payload, ok := strings.Cut(rest, ";base64,")
+	if !ok {
+		return nil, errors.New("decoding data URL failed: not base64")
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decoding data URL failed: %w", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding data URL failed: %w", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,229 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// solidImage returns a small image filled with c.
+func solidImage(c color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// redOf returns the red channel of the centre pixel of img.
+func redOf(img image.Image) uint8 {
+	r, _, _, _ := img.At(4, 4).RGBA()
+	return uint8(r >> 8)
+}
+
+// echoCanvas answers a realtime canvas request with its own image, re-encoded as PNG.
+func echoCanvas(t *testing.T, w http.ResponseWriter, req CreateLCMGenerationRequest) {
+	img, err := DecodeDataURL(req.ImageDataURL)
+	if err != nil {
+		t.Errorf("Error decoding frame: %v", err)
+		return
+	}
+	out, err := EncodeDataURL(img, "image/png")
+	if err != nil {
+		t.Errorf("Error encoding result: %v", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(CreateLCMGenerationResponse{LCMGenerationJob: &LCMGenerationJob{
+		APICreditCost:    Ptr(1),
+		ImageDataURL:     []string{out},
+		RequestTimestamp: req.RequestTimestamp,
+	}})
+}
+
+// TestCanvasSessionDebounce tests that frames sent in quick succession are coalesced.
+func TestCanvasSessionDebounce(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generations-lcm" || r.Method != "POST" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req CreateLCMGenerationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request body: %v", err)
+		}
+		if req.Prompt != "A lighthouse." || deref(req.Seed) != 42 || req.RequestTimestamp == nil {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+		if !strings.HasPrefix(req.ImageDataURL, "data:image/jpeg;base64,") {
+			t.Errorf("Expected a JPEG data URL, got %.40s", req.ImageDataURL)
+		}
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		echoCanvas(t, w, req)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.RealtimeCanvas = client.NewRealtimeCanvasService()
+
+	session := client.RealtimeCanvas.NewSession(context.Background(), CanvasSettings{Prompt: "A lighthouse.", Seed: Ptr(42)})
+	for i := uint8(1); i <= 5; i++ {
+		session.Send(solidImage(color.RGBA{R: i * 40, A: 255}))
+	}
+
+	select {
+	case result := <-session.Results():
+		if result.Err != nil {
+			t.Fatalf("Frame failed: %v", result.Err)
+		}
+		if len(result.Images) != 1 || result.APICreditCost != 1 {
+			t.Fatalf("Unexpected result: %+v", result)
+		}
+		// JPEG is lossy, so allow some drift from the last frame's red of 200.
+		if red := redOf(result.Images[0]); red < 190 || red > 210 {
+			t.Errorf("Expected the last frame, got red %d", red)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a result")
+	}
+	session.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+	if dropped := session.Dropped(); dropped != 4 {
+		t.Errorf("Expected 4 frames dropped, got %d", dropped)
+	}
+	if _, ok := <-session.Results(); ok {
+		t.Error("Expected Results to be closed")
+	}
+}
+
+// TestCanvasSessionOutOfOrder tests that a response arriving after a newer one is discarded.
+func TestCanvasSessionOutOfOrder(t *testing.T) {
+	received := make(chan struct{}, 2)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	first := true
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateLCMGenerationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request body: %v", err)
+		}
+		mu.Lock()
+		slow := first
+		first = false
+		mu.Unlock()
+		received <- struct{}{}
+		if slow {
+			<-release
+		}
+		echoCanvas(t, w, req)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.RealtimeCanvas = client.NewRealtimeCanvasService()
+
+	session := client.RealtimeCanvas.NewSession(context.Background(), CanvasSettings{Prompt: "A lighthouse.", Debounce: 10 * time.Millisecond})
+
+	session.Send(solidImage(color.RGBA{R: 40, A: 255}))
+	<-received
+	session.Send(solidImage(color.RGBA{R: 200, A: 255}))
+	<-received
+
+	select {
+	case result := <-session.Results():
+		if result.Err != nil {
+			t.Fatalf("Frame failed: %v", result.Err)
+		}
+		if red := redOf(result.Images[0]); red < 190 {
+			t.Errorf("Expected the second frame first, got red %d", red)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a result")
+	}
+
+	close(release)
+	session.Close()
+	if result, ok := <-session.Results(); ok {
+		t.Errorf("Expected the stale result to be discarded, got %+v", result)
+	}
+	if dropped := session.Dropped(); dropped != 1 {
+		t.Errorf("Expected 1 result dropped, got %d", dropped)
+	}
+}
+
+// TestCanvasSessionInpainting tests sending masked frames for inpainting.
+func TestCanvasSessionInpainting(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/lcm-inpainting" || r.Method != "POST" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req PerformInpaintingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request body: %v", err)
+		}
+		if !strings.HasPrefix(req.MaskDataURL, "data:image/png;base64,") {
+			t.Errorf("Expected a PNG mask, got %.40s", req.MaskDataURL)
+		}
+		echoCanvas(t, w, CreateLCMGenerationRequest{ImageDataURL: req.ImageDataURL, RequestTimestamp: req.RequestTimestamp})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.RealtimeCanvas = client.NewRealtimeCanvasService()
+
+	session := client.RealtimeCanvas.NewSession(context.Background(), CanvasSettings{Mode: CanvasInpaint, Prompt: "A lighthouse.", Debounce: 10 * time.Millisecond})
+	defer session.Close()
+
+	session.Send(solidImage(color.RGBA{R: 200, A: 255}))
+	if result := <-session.Results(); result.Err == nil || !strings.Contains(result.Err.Error(), "no mask") {
+		t.Errorf("Expected a missing mask error, got %v", result.Err)
+	}
+
+	session.SendMasked(solidImage(color.RGBA{R: 200, A: 255}), solidImage(color.RGBA{R: 255, G: 255, B: 255, A: 255}))
+	if result := <-session.Results(); result.Err != nil || len(result.Images) != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,354 @@
+// Package cassette provides an http.RoundTripper that records Leonardo.ai API
+// request/response pairs to a JSONL file and replays them deterministically,
+// for tests and offline development.
+//
+// Plug it into a client through its HTTPClient:
+//
+//	rec, err := cassette.New("testdata/generation.jsonl", cassette.ModeReplay)
+//	if err != nil {
+//		return err
+//	}
+//	defer rec.Close()
+//	client := leonardo.NewClient(apiKey)
+//	client.HTTPClient = &http.Client{Transport: rec}
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder replays, records, or both.
+type Mode int
+
+const (
+	// ModeReplay serves every request from the cassette and fails on unknown requests.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request to the network and appends it to the cassette.
+	ModeRecord
+	// ModeReplayOrRecord replays known requests and records the others.
+	ModeReplayOrRecord
+)
+
+// ErrNoInteraction is returned in ModeReplay when no recorded interaction matches a request.
+var ErrNoInteraction = errors.New("cassette: no recorded interaction matches request")
+
+// Matcher selects which parts of a request must be equal for a recorded interaction to match.
+type Matcher struct {
+	Method bool
+	Path   bool
+	Query  bool
+	Body   bool
+}
+
+// DefaultMatcher matches on method, path, query and body.
+var DefaultMatcher = Matcher{Method: true, Path: true, Query: true, Body: true}
+
+// Interaction is a single recorded request/response pair, stored as one JSONL line.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded form of an http.Request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// BodyEncoding is "base64" when Body holds binary data, such as an image upload.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+// Response is the recorded form of an http.Response.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// BodyEncoding is "base64" when Body holds binary data, such as a downloaded image.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+// encodeBody returns body as a string and its encoding: unchanged when it is
+// valid UTF-8, base64 otherwise, so that binary bodies survive JSON encoding.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// decodeBody reverses encodeBody.
+func decodeBody(body, encoding string) []byte {
+	if encoding == "base64" {
+		if data, err := base64.StdEncoding.DecodeString(body); err == nil {
+			return data
+		}
+	}
+	return []byte(body)
+}
+
+// Recorder is an http.RoundTripper backed by a cassette file.
+type Recorder struct {
+	// Mode selects replay and/or record behavior.
+	Mode Mode
+	// Matcher selects how requests are matched against recorded interactions.
+	Matcher Matcher
+	// Transport performs real requests when recording. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// ScrubHeaders are removed from recorded requests. Defaults to Authorization only.
+	ScrubHeaders []string
+
+	mu           sync.Mutex
+	path         string
+	interactions []Interaction
+	used         []bool
+	file         *os.File
+}
+
+// New opens the cassette at path in the given mode. Existing interactions are loaded for
+// replay; a missing file is treated as an empty cassette.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		Mode:         mode,
+		Matcher:      DefaultMatcher,
+		ScrubHeaders: []string{"Authorization"},
+		path:         path,
+	}
+
+	f, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("opening cassette failed: %w", err)
+	}
+	if f != nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var in Interaction
+			if err := json.Unmarshal(line, &in); err != nil {
+				return nil, fmt.Errorf("decoding cassette interaction %d failed: %w", len(r.interactions)+1, err)
+			}
+			r.interactions = append(r.interactions, in)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading cassette failed: %w", err)
+		}
+	}
+	r.used = make([]bool, len(r.interactions))
+
+	return r, nil
+}
+
+// Interactions returns the interactions currently held by the cassette.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Close closes the cassette file if it was opened for recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Mode != ModeRecord {
+		if in, ok := r.replay(req, body); ok {
+			return in.Response.toHTTP(req), nil
+		}
+		if r.Mode == ModeReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+		}
+	}
+
+	return r.record(req, body)
+}
+
+// replay finds the first unused interaction matching the request and marks it used.
+func (r *Recorder) replay(req *http.Request, body []byte) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || !r.Matcher.matches(in.Request, req, body) {
+			continue
+		}
+		r.used[i] = true
+		return in, true
+	}
+	return Interaction{}, false
+}
+
+// record performs the request and appends the scrubbed interaction to the cassette.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response body failed: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	for _, h := range r.ScrubHeaders {
+		header.Del(h)
+	}
+	in := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: header,
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+		},
+	}
+	in.Request.Body, in.Request.BodyEncoding = encodeBody(body)
+	in.Response.Body, in.Response.BodyEncoding = encodeBody(respBody)
+
+	if err := r.append(in); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) append(in Interaction) error {
+	line, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encoding cassette interaction failed: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("opening cassette for recording failed: %w", err)
+		}
+		r.file = f
+	}
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing cassette interaction failed: %w", err)
+	}
+	r.interactions = append(r.interactions, in)
+	r.used = append(r.used, true)
+	return nil
+}
+
+// matches reports whether the recorded request matches the live one.
+func (m Matcher) matches(rec Request, req *http.Request, body []byte) bool {
+	if m.Method && rec.Method != req.Method {
+		return false
+	}
+	u, err := url.Parse(rec.URL)
+	if err != nil {
+		return false
+	}
+	if m.Path && u.Path != req.URL.Path {
+		return false
+	}
+	if m.Query && !reflect.DeepEqual(u.Query(), req.URL.Query()) {
+		return false
+	}
+	if m.Body && !bodiesEqual(
+		withoutBoundary(decodeBody(rec.Body, rec.BodyEncoding), rec.Header.Get("Content-Type")),
+		withoutBoundary(body, req.Header.Get("Content-Type")),
+	) {
+		return false
+	}
+	return true
+}
+
+// bodiesEqual compares JSON bodies semantically and other bodies byte for byte.
+func bodiesEqual(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// withoutBoundary replaces the boundary of a multipart body, which is random
+// for every request, with a fixed one so that uploads can match.
+func withoutBoundary(body []byte, contentType string) []byte {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body
+	}
+	return bytes.ReplaceAll(body, []byte(params["boundary"]), []byte("boundary"))
+}
+
+// readRequestBody reads the request body and restores it for the real transport.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading request body failed: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	body := decodeBody(r.Body, r.BodyEncoding)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
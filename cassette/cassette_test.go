@@ -0,0 +1,168 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emmaly/leonardo"
+)
+
+// TestRecordAndReplay tests that interactions recorded against a server replay without it.
+func TestRecordAndReplay(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generations" || r.Method != "POST" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sdGenerationJob":{"generationId":"gen-123","apiCreditCost":8}}`))
+	})
+
+	server := httptest.NewServer(handler)
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	// Record
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	rec.Transport = server.Client().Transport
+	client := leonardo.NewClient("secret-api-key")
+	client.BaseURL = server.URL
+	client.HTTPClient = &http.Client{Transport: rec}
+
+	req := leonardo.CreateGenerationRequest{Prompt: "A red fox in the snow."}
+	if _, err := client.Images.CreateImageGeneration(context.Background(), req); err != nil {
+		t.Fatalf("CreateImageGeneration failed: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading cassette failed: %v", err)
+	}
+	if strings.Contains(string(data), "secret-api-key") {
+		t.Error("Expected Authorization header to be scrubbed from the cassette")
+	}
+
+	// Replay
+	rec, err = New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	client.HTTPClient = &http.Client{Transport: rec}
+
+	resp, err := client.Images.CreateImageGeneration(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Replayed CreateImageGeneration failed: %v", err)
+	}
+	if resp.SDGenerationJob.GenerationID == nil || *resp.SDGenerationJob.GenerationID != "gen-123" {
+		t.Errorf("Expected GenerationID 'gen-123', got %v", resp.SDGenerationJob.GenerationID)
+	}
+
+	// Each interaction replays only once
+	_, err = client.Images.CreateImageGeneration(context.Background(), req)
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+}
+
+// TestMatcher tests the configurable request matching.
+func TestMatcher(t *testing.T) {
+	rec := Request{
+		Method: "GET",
+		URL:    "https://cloud.leonardo.ai/api/rest/v1/platformModels?limit=10&offset=0",
+	}
+	req, _ := http.NewRequest("GET", "https://cloud.leonardo.ai/api/rest/v1/platformModels?offset=0&limit=10", nil)
+	if !DefaultMatcher.matches(rec, req, nil) {
+		t.Error("Expected query parameters in a different order to match")
+	}
+
+	req, _ = http.NewRequest("GET", "https://cloud.leonardo.ai/api/rest/v1/platformModels?limit=20", nil)
+	if DefaultMatcher.matches(rec, req, nil) {
+		t.Error("Expected different query parameters not to match")
+	}
+	if !(Matcher{Method: true, Path: true}).matches(rec, req, nil) {
+		t.Error("Expected a match when query matching is disabled")
+	}
+
+	if !bodiesEqual([]byte(`{"a":1,"b":2}`), []byte(`{"b":2, "a":1}`)) {
+		t.Error("Expected equivalent JSON bodies to match")
+	}
+}
+
+// TestBinaryAndMultipartBodies tests that binary bodies replay intact and that
+// multipart uploads match despite their random boundaries.
+func TestBinaryAndMultipartBodies(t *testing.T) {
+	image := []byte{0x89, 0x50, 0x4e, 0x47, 0xff, 0xd8, 0x00, 0x80}
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image.png":
+			w.Write(image)
+		case "/upload":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	upload := func(client *http.Client) (*http.Response, error) {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		w.WriteField("key", "uploads/init.png")
+		part, _ := w.CreateFormFile("file", "init.png")
+		part.Write(image)
+		w.Close()
+		return client.Post(server.URL+"/upload", w.FormDataContentType(), &body)
+	}
+
+	// Record
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	rec.Transport = server.Client().Transport
+	client := &http.Client{Transport: rec}
+	if _, err := client.Get(server.URL + "/image.png"); err != nil {
+		t.Fatalf("Recording download failed: %v", err)
+	}
+	if _, err := upload(client); err != nil {
+		t.Fatalf("Recording upload failed: %v", err)
+	}
+	rec.Close()
+	server.Close()
+
+	// Replay
+	rec, err = New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	client = &http.Client{Transport: rec}
+	resp, err := client.Get(server.URL + "/image.png")
+	if err != nil {
+		t.Fatalf("Replayed download failed: %v", err)
+	}
+	if data, _ := io.ReadAll(resp.Body); !bytes.Equal(data, image) {
+		t.Errorf("Expected % x, got % x", image, data)
+	}
+	if resp, err := upload(client); err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected the upload to replay, got %v", err)
+	}
+}
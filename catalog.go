@@ -0,0 +1,270 @@
+package leonardo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ModelInfo describes a platform model and what it can be used with.
+type ModelInfo struct {
+	ID          string
+	Name        string
+	Aliases     []string // other names the model is known by
+	Description string
+	SDVersion   SDVersion
+
+	// Dimensions the model generates at by default and the largest width or
+	// height it accepts.
+	DefaultWidth  int
+	DefaultHeight int
+	MaxDimension  int
+
+	Alchemy    bool // supports Alchemy
+	PhotoReal  bool // supports PhotoReal v2
+	Ultra      bool // supports Ultra mode
+	Elements   bool // supports elements (LoRAs)
+	ControlNet bool // supports ControlNet image guidance
+}
+
+// KnownModels is the built-in table of well-known platform models.
+var KnownModels = []ModelInfo{
+	{
+		ID: "de7d3faf-762f-48e0-b3b7-9d0ac3a3fcf3", Name: "Leonardo Phoenix 1.0", Aliases: []string{"Leonardo Phoenix", "Phoenix"},
+		Description: "Prompt adherence and text rendering.", SDVersion: SDVersionPhoenix,
+		DefaultWidth: 1472, DefaultHeight: 832, MaxDimension: 1536,
+		Alchemy: true, Ultra: true,
+	},
+	{
+		ID: "6b645e3a-d64f-4341-a6d8-7a3690fbf042", Name: "Leonardo Phoenix 0.9",
+		Description: "Prompt adherence and text rendering.", SDVersion: SDVersionPhoenix,
+		DefaultWidth: 1472, DefaultHeight: 832, MaxDimension: 1536,
+		Alchemy: true, Ultra: true,
+	},
+	{
+		ID: "b24e16ff-06e3-43eb-8d33-4416c2d75876", Name: "Leonardo Lightning XL", Aliases: []string{"Lightning XL"},
+		Description: "A versatile and fast model.", SDVersion: SDVersionSDXL_LIGHTNING,
+		DefaultWidth: 1024, DefaultHeight: 768, MaxDimension: 1024,
+		Alchemy: true, Elements: true, ControlNet: true,
+	},
+	{
+		ID: "aa77f04e-3eec-4034-9c07-d0f619684628", Name: "Leonardo Kino XL", Aliases: []string{"Kino XL"},
+		Description: "Cinematic outputs.", SDVersion: SDVersionSDXL_1_0,
+		DefaultWidth: 1024, DefaultHeight: 768, MaxDimension: 1024,
+		Alchemy: true, PhotoReal: true, Elements: true, ControlNet: true,
+	},
+	{
+		ID: "5c232a9e-9061-4777-980a-ddc8e65647c6", Name: "Leonardo Vision XL", Aliases: []string{"Vision XL"},
+		Description: "Photographic realism.", SDVersion: SDVersionSDXL_1_0,
+		DefaultWidth: 1024, DefaultHeight: 768, MaxDimension: 1024,
+		Alchemy: true, PhotoReal: true, Elements: true, ControlNet: true,
+	},
+	{
+		ID: "1e60896f-3c26-4296-8ecc-53e2afecc132", Name: "Leonardo Diffusion XL", Aliases: []string{"Diffusion XL"},
+		Description: "A general purpose model.", SDVersion: SDVersionSDXL_0_9,
+		DefaultWidth: 1024, DefaultHeight: 768, MaxDimension: 1024,
+		Alchemy: true, PhotoReal: true, Elements: true, ControlNet: true,
+	},
+	{
+		ID: "e71a1c2f-4f80-4800-934f-2c68979d8cc8", Name: "Leonardo Anime XL", Aliases: []string{"Anime XL"},
+		Description: "Anime and illustration.", SDVersion: SDVersionSDXL_LIGHTNING,
+		DefaultWidth: 1024, DefaultHeight: 768, MaxDimension: 1024,
+		Alchemy: true, Elements: true, ControlNet: true,
+	},
+	{
+		ID: "2067ae52-33fd-4a82-bb92-c2c55e7d2786", Name: "AlbedoBase XL",
+		Description: "A general purpose community model.", SDVersion: SDVersionSDXL_0_9,
+		DefaultWidth: 1024, DefaultHeight: 768, MaxDimension: 1024,
+		Alchemy: true, Elements: true, ControlNet: true,
+	},
+	{
+		ID: "16e7060a-803e-4df3-97ee-edcfa5dc9cc8", Name: "SDXL 1.0",
+		Description: "Stable Diffusion XL 1.0.", SDVersion: SDVersionSDXL_1_0,
+		DefaultWidth: 1024, DefaultHeight: 768, MaxDimension: 1024,
+		Alchemy: true, Elements: true, ControlNet: true,
+	},
+	{
+		ID: "ac614f96-1082-45bf-be9d-757f2d31c174", Name: "DreamShaper v7",
+		Description: "A versatile Stable Diffusion 1.5 model.", SDVersion: SDVersionV1_5,
+		DefaultWidth: 512, DefaultHeight: 512, MaxDimension: 1024,
+		Alchemy: true, ControlNet: true,
+	},
+	{
+		ID: "e316348f-7773-490e-adcd-46757c738eb7", Name: "Absolute Reality v1.6",
+		Description: "Photorealistic Stable Diffusion 1.5 model.", SDVersion: SDVersionV1_5,
+		DefaultWidth: 512, DefaultHeight: 512, MaxDimension: 1024,
+		Alchemy: true, ControlNet: true,
+	},
+}
+
+// DefaultModelID is the model the API uses when CreateGenerationRequest.ModelID is unset.
+const DefaultModelID = "b24e16ff-06e3-43eb-8d33-4416c2d75876"
+
+// ModelByName looks up a model in KnownModels by name or alias, ignoring case.
+func ModelByName(name string) (ModelInfo, bool) {
+	return findModel(KnownModels, name)
+}
+
+// ModelByID looks up a model in KnownModels by ID.
+func ModelByID(id string) (ModelInfo, bool) {
+	for _, m := range KnownModels {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return ModelInfo{}, false
+}
+
+// findModel returns the model named name, preferring names over aliases.
+func findModel(models []ModelInfo, name string) (ModelInfo, bool) {
+	name = strings.TrimSpace(name)
+	for _, m := range models {
+		if strings.EqualFold(m.Name, name) {
+			return m, true
+		}
+	}
+	for _, m := range models {
+		for _, alias := range m.Aliases {
+			if strings.EqualFold(alias, name) {
+				return m, true
+			}
+		}
+	}
+	return ModelInfo{}, false
+}
+
+// ModelCatalog combines the models listed by ListPlatformModels with the
+// capabilities in KnownModels. The list is fetched on first use and cached until
+// TTL elapses or Refresh is called.
+type ModelCatalog struct {
+	client *Client
+
+	// TTL is how long the fetched list is used before it is fetched again. Zero
+	// means it is kept until Refresh is called.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	models  []ModelInfo
+	fetched time.Time
+}
+
+// NewModelCatalog creates a new ModelCatalog.
+func (c *Client) NewModelCatalog() *ModelCatalog {
+	return &ModelCatalog{client: c}
+}
+
+// catalogPageSize is the number of platform models fetched per request.
+const catalogPageSize = 50
+
+// Refresh fetches the platform models again.
+func (m *ModelCatalog) Refresh(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.refresh(ctx)
+}
+
+func (m *ModelCatalog) refresh(ctx context.Context) error {
+	var platform []PlatformModel
+	for offset := 0; ; offset += catalogPageSize {
+		resp, err := m.client.Models.ListPlatformModels(ctx, PaginationParams{Limit: catalogPageSize, Offset: offset})
+		if err != nil {
+			return fmt.Errorf("refreshing model catalog failed: %w", err)
+		}
+		platform = append(platform, resp.CustomModels...)
+		if len(resp.CustomModels) < catalogPageSize {
+			break
+		}
+	}
+
+	m.models = mergeModels(platform)
+	m.fetched = time.Now()
+	return nil
+}
+
+// Models returns every model in the catalog, fetching the platform models if needed.
+func (m *ModelCatalog) Models(ctx context.Context) ([]ModelInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.models == nil || (m.TTL > 0 && time.Since(m.fetched) > m.TTL) {
+		if err := m.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return append([]ModelInfo(nil), m.models...), nil
+}
+
+// ModelByName looks up a model by name or alias, ignoring case.
+func (m *ModelCatalog) ModelByName(ctx context.Context, name string) (ModelInfo, error) {
+	models, err := m.Models(ctx)
+	if err != nil {
+		return ModelInfo{}, err
+	}
+	if model, ok := findModel(models, name); ok {
+		return model, nil
+	}
+	return ModelInfo{}, fmt.Errorf("model %q not found", name)
+}
+
+// ModelByID looks up a model by ID.
+func (m *ModelCatalog) ModelByID(ctx context.Context, id string) (ModelInfo, error) {
+	models, err := m.Models(ctx)
+	if err != nil {
+		return ModelInfo{}, err
+	}
+	for _, model := range models {
+		if model.ID == id {
+			return model, nil
+		}
+	}
+	return ModelInfo{}, fmt.Errorf("model %s not found", id)
+}
+
+// mergeModels returns the platform models annotated with the capabilities of
+// the matching known model, followed by the known models the platform didn't list.
+// Platform names and descriptions take precedence over the built-in ones.
+func mergeModels(platform []PlatformModel) []ModelInfo {
+	models := make([]ModelInfo, 0, len(platform)+len(KnownModels))
+	listed := map[string]bool{}
+	for _, p := range platform {
+		id := deref(p.ID)
+		listed[id] = true
+
+		m, ok := ModelByID(id)
+		if !ok {
+			m = inferModel(id, SDVersion(deref(p.BaseModel)))
+		}
+		if name := deref(p.Name); name != "" {
+			m.Name = name
+		}
+		if desc := deref(p.Description); desc != "" {
+			m.Description = desc
+		}
+		models = append(models, m)
+	}
+	for _, m := range KnownModels {
+		if !listed[m.ID] {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// inferModel guesses the capabilities of a model not in KnownModels from its base model.
+func inferModel(id string, sdVersion SDVersion) ModelInfo {
+	m := ModelInfo{ID: id, SDVersion: sdVersion, Alchemy: true}
+	switch {
+	case strings.HasPrefix(string(sdVersion), "SDXL"):
+		m.DefaultWidth, m.DefaultHeight, m.MaxDimension = 1024, 768, 1024
+		m.Elements, m.ControlNet = true, true
+	case sdVersion == SDVersionPhoenix:
+		m.DefaultWidth, m.DefaultHeight, m.MaxDimension = 1472, 832, 1536
+		m.Ultra = true
+	default:
+		m.DefaultWidth, m.DefaultHeight, m.MaxDimension = 512, 512, 1024
+		m.ControlNet = true
+	}
+	return m
+}
@@ -0,0 +1,96 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestModelByName tests looking up built-in models by name and alias.
+func TestModelByName(t *testing.T) {
+	m, ok := ModelByName("leonardo phoenix")
+	if !ok || m.ID != "de7d3faf-762f-48e0-b3b7-9d0ac3a3fcf3" || !m.Ultra {
+		t.Errorf("Expected Leonardo Phoenix 1.0 with Ultra support, got %+v", m)
+	}
+	m, ok = ModelByName("Leonardo Kino XL")
+	if !ok || !m.PhotoReal || m.SDVersion != SDVersionSDXL_1_0 {
+		t.Errorf("Expected Leonardo Kino XL with PhotoReal support, got %+v", m)
+	}
+	if _, ok := ModelByName("Nonexistent Model"); ok {
+		t.Error("Expected unknown model not to be found")
+	}
+	if m, ok := ModelByID(DefaultModelID); !ok || m.Name != "Leonardo Lightning XL" {
+		t.Errorf("Expected the default model to be Leonardo Lightning XL, got %+v", m)
+	}
+}
+
+// TestModelCatalog tests the cached catalog built from ListPlatformModels.
+func TestModelCatalog(t *testing.T) {
+	requests := 0
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/platformModels" || r.Method != "GET" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		requests++
+
+		// Mock response
+		w.Header().Set("Content-Type", "application/json")
+		response := ListPlatformModelsResponse{
+			CustomModels: []PlatformModel{
+				{ID: Ptr("aa77f04e-3eec-4034-9c07-d0f619684628"), Name: Ptr("Leonardo Kino XL 2"), BaseModel: Ptr("SDXL_1_0")},
+				{ID: Ptr("model-new"), Name: Ptr("Brand New XL"), BaseModel: Ptr("SDXL_1_0")},
+			},
+		}
+		json.NewEncoder(w).Encode(response)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Models = client.NewModelsService()
+	catalog := client.NewModelCatalog()
+
+	ctx := context.Background()
+	kino, err := catalog.ModelByName(ctx, "Leonardo Kino XL 2")
+	if err != nil {
+		t.Fatalf("ModelByName failed: %v", err)
+	}
+	if kino.ID != "aa77f04e-3eec-4034-9c07-d0f619684628" || !kino.PhotoReal {
+		t.Errorf("Expected platform name merged with built-in capabilities, got %+v", kino)
+	}
+
+	brandNew, err := catalog.ModelByID(ctx, "model-new")
+	if err != nil {
+		t.Fatalf("ModelByID failed: %v", err)
+	}
+	if !brandNew.Elements || brandNew.MaxDimension != 1024 {
+		t.Errorf("Expected capabilities inferred from the SDXL base model, got %+v", brandNew)
+	}
+
+	if _, err := catalog.ModelByName(ctx, "Leonardo Phoenix"); err != nil {
+		t.Errorf("Expected built-in models not listed by the platform to be included: %v", err)
+	}
+	if _, err := catalog.ModelByName(ctx, "Nonexistent Model"); err == nil {
+		t.Error("Expected error for unknown model, got nil")
+	}
+	if requests != 1 {
+		t.Errorf("Expected the platform models to be fetched once, got %d requests", requests)
+	}
+
+	if err := catalog.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected Refresh to fetch the platform models again, got %d requests", requests)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -16,6 +17,35 @@ type Client struct {
 	HTTPClient *http.Client
 	APIKey     string
 
+	// KeyProvider, when set, supplies the API key of each request in place of
+	// APIKey; see KeyPool.
+	KeyProvider KeyProvider
+
+	// Middleware wraps every service call; see Use.
+	Middleware []Middleware
+
+	// Logger, when set, receives a record for every request made by the client.
+	// LogBodies adds the request and response bodies to those records, with the
+	// API key redacted and base64 image data truncated.
+	Logger    *slog.Logger
+	LogBodies bool
+
+	// Metrics, when set, receives request counts, latencies, error classes,
+	// retries, credits spent and jobs in flight per operation.
+	Metrics Metrics
+
+	// Tracer, when set, starts a span for every service call and workflow.
+	Tracer Tracer
+
+	// DryRun, when set, skips the network for mutating calls and records them instead.
+	DryRun *DryRun
+
+	// RateLimiter, when set, holds requests back to stay within per-group rate limits.
+	RateLimiter *RateLimiter
+
+	// CircuitBreaker, when set, fails requests fast while the API is failing them.
+	CircuitBreaker *CircuitBreaker
+
 	// Services
 	Datasets          *DatasetsService
 	Images            *ImagesService
@@ -30,6 +60,11 @@ type Client struct {
 	User              *UserService
 	Variation         *VariationService
 	Motion            *MotionService
+
+	// Catalog caches the platform models and their capabilities.
+	Catalog *ModelCatalog
+
+	jobs jobTracker
 }
 
 // NewClient creates a new Leonardo.ai API client.
@@ -57,6 +92,8 @@ func NewClient(apiKey string) *Client {
 	c.Variation = c.NewVariationService()
 	c.Motion = c.NewMotionService()
 
+	c.Catalog = c.NewModelCatalog()
+
 	return c
 }
 
@@ -76,8 +113,15 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body inter
 		return nil, err
 	}
 
+	apiKey := c.APIKey
+	if c.KeyProvider != nil {
+		if apiKey, err = c.KeyProvider.APIKey(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -87,25 +131,86 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body inter
 // Do sends an HTTP request and decodes the response into v.
 // It also handles API-specific error responses.
 func (c *Client) Do(req *http.Request, v interface{}) error {
+	call, _ := CallFromContext(req.Context())
+	if call != nil {
+		call.attempt++
+	}
+
+	var reqBody []byte
+	var respBody *bytes.Buffer
+	var tee io.Writer
+	if c.Logger != nil && c.LogBodies {
+		reqBody = peekRequestBody(req)
+		respBody = &bytes.Buffer{}
+		tee = respBody
+	}
+	intercepted := c.DryRun != nil && c.DryRun.intercepts(req)
+	if call != nil {
+		call.intercepted = intercepted
+	}
+	if c.RateLimiter != nil && !intercepted {
+		if err := c.RateLimiter.Wait(req.Context(), requestGroup(req)); err != nil {
+			return err
+		}
+	}
+	done := func(error) {}
+	if c.CircuitBreaker != nil && !intercepted {
+		var err error
+		if done, err = c.CircuitBreaker.allow(requestGroup(req)); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	status, err := c.do(req, v, tee)
+	latency := time.Since(start)
+	done(err)
+	if status != 0 {
+		spanFromContext(req.Context()).SetAttributes(Attr("http.status_code", status))
+	}
+	if c.Metrics != nil && !intercepted {
+		c.observe(call, status, latency, v, err)
+	}
+	if c.Logger != nil {
+		c.logRequest(req, call, status, latency, reqBody, respBody, v, err)
+	}
+	return err
+}
+
+// do performs the request, returning the HTTP status code. When tee is non-nil
+// the raw response body is copied into it.
+func (c *Client) do(req *http.Request, v interface{}, tee io.Writer) (int, error) {
+	if c.DryRun != nil && c.DryRun.intercepts(req) {
+		return 0, c.DryRun.do(c, req, v)
+	}
+
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer resp.Body.Close()
+	if c.RateLimiter != nil {
+		c.RateLimiter.observe(requestGroup(req), resp)
+	}
+
+	body := io.Reader(resp.Body)
+	if tee != nil {
+		body = io.TeeReader(resp.Body, tee)
+		defer io.Copy(io.Discard, body)
+	}
 
 	// Attempt to decode the response
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var apiErr APIErrorResponse
-		// io.Copy(os.Stdout, resp.Body)
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(body).Decode(&apiErr.APIErrorResponse); err != nil {
+			return resp.StatusCode, &APIError{StatusCode: resp.StatusCode}
 		}
-		return fmt.Errorf("API Error %s: %s", apiErr.Code, apiErr.Message)
+		return resp.StatusCode, apiErr
 	}
 
 	// Decode successful response
 	if v != nil {
-		return json.NewDecoder(resp.Body).Decode(v)
+		return resp.StatusCode, json.NewDecoder(body).Decode(v)
 	}
-	return nil
+	return resp.StatusCode, nil
 }
@@ -27,12 +27,14 @@ func (s *DatasetsService) CreateDataset(ctx context.Context, req CreateDatasetRe
 	var resp CreateDatasetResponse
 	path := "/datasets"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateDataset request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Datasets",
+		Operation: "CreateDataset",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating dataset failed: %w", err)
 	}
@@ -46,12 +48,14 @@ func (s *DatasetsService) GetDataset(ctx context.Context, id string) (*GetDatase
 	var resp GetDatasetResponse
 	path := fmt.Sprintf("/datasets/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating GetDataset request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Datasets",
+		Operation: "GetDataset",
+		Method:    http.MethodGet,
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving dataset failed: %w", err)
 	}
@@ -65,12 +69,14 @@ func (s *DatasetsService) DeleteDataset(ctx context.Context, id string) (*Delete
 	var resp DeleteDatasetResponse
 	path := fmt.Sprintf("/datasets/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating DeleteDataset request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Datasets",
+		Operation: "DeleteDataset",
+		Method:    http.MethodDelete,
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("deleting dataset failed: %w", err)
 	}
@@ -84,12 +90,15 @@ func (s *DatasetsService) UploadDatasetImage(ctx context.Context, datasetID stri
 	var resp UploadDatasetImageResponse
 	path := fmt.Sprintf("/datasets/%s/upload", urlPathEscape(datasetID))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating UploadDatasetImage request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Datasets",
+		Operation: "UploadDatasetImage",
+		Method:    http.MethodPost,
+		Path:      path,
+		ID:        datasetID,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving upload details failed: %w", err)
 	}
@@ -100,6 +109,19 @@ func (s *DatasetsService) UploadDatasetImage(ctx context.Context, datasetID stri
 // UploadImageToS3 uploads the image data to S3 using the presigned URL.
 // This is a helper function and not directly interacting with Leonardo.ai API.
 func UploadImageToS3(s3URL string, fields map[string]string, imagePath string) error {
+	return uploadImageToS3(context.Background(), &http.Client{}, s3URL, fields, imagePath)
+}
+
+// UploadToS3 uploads the image data to S3 using the presigned URL returned by an
+// upload endpoint, within an "S3.Upload" span, using the client's HTTPClient.
+func (c *Client) UploadToS3(ctx context.Context, s3URL string, fields map[string]string, imagePath string) (err error) {
+	ctx, span := c.startSpan(ctx, "S3.Upload", Attr("http.url", s3URL), Attr("leonardo.file", imagePath))
+	defer func() { endSpan(span, err) }()
+
+	return uploadImageToS3(ctx, c.HTTPClient, s3URL, fields, imagePath)
+}
+
+func uploadImageToS3(ctx context.Context, client *http.Client, s3URL string, fields map[string]string, imagePath string) error {
 	file, err := os.Open(imagePath)
 	if err != nil {
 		return fmt.Errorf("opening image file failed: %w", err)
@@ -138,14 +160,13 @@ func UploadImageToS3(s3URL string, fields map[string]string, imagePath string) e
 	}
 
 	// Create the upload request
-	req, err := http.NewRequest(http.MethodPost, s3URL, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s3URL, body)
 	if err != nil {
 		return fmt.Errorf("creating S3 upload request failed: %w", err)
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// Execute the request
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("S3 upload request failed: %w", err)
@@ -166,12 +187,15 @@ func (s *DatasetsService) UploadGeneratedImageToDataset(ctx context.Context, dat
 	var resp UploadGeneratedImageResponse
 	path := fmt.Sprintf("/datasets/%s/upload/gen", urlPathEscape(datasetID))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating UploadGeneratedImageToDataset request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Datasets",
+		Operation: "UploadGeneratedImageToDataset",
+		Method:    http.MethodPost,
+		Path:      path,
+		ID:        datasetID,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("uploading generated image to dataset failed: %w", err)
 	}
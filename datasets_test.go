@@ -33,9 +33,7 @@ func TestCreateDataset(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := CreateDatasetResponse{
-			InsertDatasetsOne: struct {
-				ID *string `json:"id"`
-			}{
+			InsertDatasetsOne: ResourceID{
 				ID: Ptr("dataset-123"),
 			},
 		}
@@ -83,31 +81,16 @@ func TestGetDataset(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := GetDatasetResponse{
-			DatasetsByPk: struct {
-				CreatedAt     *Time `json:"createdAt"`
-				DatasetImages []struct {
-					CreatedAt *Time   `json:"createdAt"`
-					ID        *string `json:"id"`
-					URL       *string `json:"url"`
-				} `json:"dataset_images"`
-				Description *string `json:"description"`
-				ID          *string `json:"id"`
-				Name        *string `json:"name"`
-				UpdatedAt   *Time   `json:"updatedAt"`
-			}{
-				CreatedAt: &Time{time.Now().Add(-24 * time.Hour)},
-				DatasetImages: []struct {
-					CreatedAt *Time   `json:"createdAt"`
-					ID        *string `json:"id"`
-					URL       *string `json:"url"`
-				}{
+			DatasetsByPk: Dataset{
+				CreatedAt: &Time{Time: time.Now().Add(-24 * time.Hour)},
+				DatasetImages: []DatasetImage{
 					{
-						CreatedAt: &Time{time.Now().Add(-23 * time.Hour)},
+						CreatedAt: &Time{Time: time.Now().Add(-23 * time.Hour)},
 						ID:        Ptr("img-001"),
 						URL:       Ptr("https://s3.amazonaws.com/bucket/img-001.jpg"),
 					},
 					{
-						CreatedAt: &Time{time.Now().Add(-22 * time.Hour)},
+						CreatedAt: &Time{Time: time.Now().Add(-22 * time.Hour)},
 						ID:        Ptr("img-002"),
 						URL:       Ptr("https://s3.amazonaws.com/bucket/img-002.jpg"),
 					},
@@ -115,7 +98,7 @@ func TestGetDataset(t *testing.T) {
 				Description: Ptr("A test dataset description."),
 				ID:          Ptr("dataset-123"),
 				Name:        Ptr("Test Dataset"),
-				UpdatedAt:   &Time{time.Now()},
+				UpdatedAt:   &Time{Time: time.Now()},
 			},
 		}
 		json.NewEncoder(w).Encode(response)
@@ -163,9 +146,7 @@ func TestDeleteDataset(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := DeleteDatasetResponse{
-			DeleteDatasetsByPK: struct {
-				ID *string `json:"id"`
-			}{
+			DeleteDatasetsByPK: ResourceID{
 				ID: Ptr("dataset-123"),
 			},
 		}
@@ -217,12 +198,7 @@ func TestUploadDatasetImage(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := UploadDatasetImageResponse{
-			UploadDatasetImage: &struct {
-				Fields map[string]string `json:"fields"`
-				ID     *string           `json:"id"`
-				Key    *string           `json:"key"`
-				URL    *string           `json:"url"`
-			}{
+			UploadDatasetImage: &DatasetImageUpload{
 				Fields: map[string]string{
 					"key":       "uploads/dataset-123/image-001.jpg",
 					"policy":    "policy-string",
@@ -298,9 +274,7 @@ func TestUploadGeneratedImageToDataset(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := UploadGeneratedImageResponse{
-			UploadDatasetImageFromGen: &struct {
-				ID *string `json:"id"`
-			}{
+			UploadDatasetImageFromGen: &ResourceID{
 				ID: Ptr("uploaded-gen-456"),
 			},
 		}
@@ -0,0 +1,224 @@
+package leonardo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Limits on the Width and Height of a generation request. Models listed with a
+// larger ModelInfo.MaxDimension accept sizes up to that instead.
+const (
+	MinDimension  = 32
+	MaxDimension  = 1024
+	DimensionStep = 8
+
+	// MaxOutputDimension is the largest width or height of an image after the
+	// HighResolution, Ultra and UpscaleRatio options are applied.
+	MaxOutputDimension = 4096
+)
+
+// DefaultWidth and DefaultHeight are the size the API generates at when a
+// request leaves Width and Height unset, whatever the model.
+const (
+	DefaultWidth  = 1024
+	DefaultHeight = 768
+)
+
+// Output scale factors of the options that enlarge a generation.
+const (
+	HighResolutionScale = 1.5
+	UltraScale          = 2.0
+)
+
+// DimensionRequest describes the image size wanted from PlanDimensions.
+type DimensionRequest struct {
+	// AspectRatio is width divided by height, e.g. 16.0 / 9. Zero uses the
+	// aspect ratio of the model's default size.
+	AspectRatio float64
+
+	// Pixels is the target width times height before upscaling. Zero uses the
+	// pixel count of the model's default size.
+	Pixels int
+
+	// Model is the model the image is generated with. The zero value applies
+	// the limits of an unknown SDXL model.
+	Model ModelInfo
+
+	HighResolution bool
+	Ultra          bool
+	UpscaleRatio   int // zero for none
+}
+
+// Dimensions are the Width and Height to request and the size of the image the
+// API is expected to return once upscale options are applied.
+type Dimensions struct {
+	Width        int
+	Height       int
+	OutputWidth  int
+	OutputHeight int
+}
+
+// PlanDimensions returns legal Width and Height values as close as possible to
+// the requested aspect ratio and pixel count.
+func PlanDimensions(req DimensionRequest) (Dimensions, error) {
+	var upscaleRatio *int
+	if req.UpscaleRatio != 0 {
+		upscaleRatio = &req.UpscaleRatio
+	}
+	if err := errors.Join(checkUpscaleOptions(req.Model, req.HighResolution, req.Ultra, upscaleRatio)...); err != nil {
+		return Dimensions{}, err
+	}
+	if req.AspectRatio < 0 || req.Pixels < 0 {
+		return Dimensions{}, errors.New("aspect ratio and pixels must not be negative")
+	}
+
+	defWidth, defHeight := req.Model.DefaultWidth, req.Model.DefaultHeight
+	if defWidth == 0 || defHeight == 0 {
+		defWidth, defHeight = DefaultWidth, DefaultHeight
+	}
+	aspect := req.AspectRatio
+	if aspect == 0 {
+		aspect = float64(defWidth) / float64(defHeight)
+	}
+	pixels := req.Pixels
+	if pixels == 0 {
+		pixels = defWidth * defHeight
+	}
+
+	scale := outputScale(req.HighResolution, req.Ultra, req.UpscaleRatio)
+	limit := maxDimension(req.Model)
+	if byOutput := int(MaxOutputDimension / scale); byOutput < limit {
+		limit = byOutput
+	}
+	limit -= limit % DimensionStep
+
+	w := math.Sqrt(float64(pixels) * aspect)
+	h := w / aspect
+	if fit := float64(limit) / math.Max(w, h); fit < 1 {
+		w, h = w*fit, h*fit
+	}
+
+	d := Dimensions{Width: roundDimension(w, limit), Height: roundDimension(h, limit)}
+	d.OutputWidth, d.OutputHeight = scaleDimensions(d.Width, d.Height, scale)
+	return d, nil
+}
+
+// OutputSize returns the expected size of the images returned for r, after the
+// HighResolution, Ultra and UpscaleRatio options are applied. Unset dimensions
+// use the API's default size.
+func (r CreateGenerationRequest) OutputSize() (width, height int) {
+	width, height = r.size()
+	return scaleDimensions(width, height, outputScale(deref(r.HighResolution), deref(r.Ultra), deref(r.UpscaleRatio)))
+}
+
+// model returns what is known about the request's model.
+func (r CreateGenerationRequest) model() ModelInfo {
+	id := DefaultModelID
+	if r.ModelID != nil {
+		id = *r.ModelID
+	}
+	if m, ok := ModelByID(id); ok {
+		return m
+	}
+	return inferModel(id, deref(r.SDVersion))
+}
+
+// size returns the requested width and height, defaulting to the API's default size.
+func (r CreateGenerationRequest) size() (width, height int) {
+	width, height = DefaultWidth, DefaultHeight
+	if r.Width != nil {
+		width = *r.Width
+	}
+	if r.Height != nil {
+		height = *r.Height
+	}
+	return width, height
+}
+
+// checkDimensions returns the problems with requesting a width x height image
+// from model with the given upscale options.
+func checkDimensions(width, height int, model ModelInfo, highResolution, ultra bool, upscaleRatio *int) []error {
+	errs := checkUpscaleOptions(model, highResolution, ultra, upscaleRatio)
+
+	limit := maxDimension(model)
+	for _, dim := range []struct {
+		name  string
+		value int
+	}{{"width", width}, {"height", height}} {
+		if dim.value < MinDimension || dim.value > limit {
+			errs = append(errs, fmt.Errorf("%s %d must be between %d and %d", dim.name, dim.value, MinDimension, limit))
+		} else if dim.value%DimensionStep != 0 {
+			errs = append(errs, fmt.Errorf("%s %d must be a multiple of %d", dim.name, dim.value, DimensionStep))
+		}
+	}
+
+	outWidth, outHeight := scaleDimensions(width, height, outputScale(highResolution, ultra, deref(upscaleRatio)))
+	if outWidth > MaxOutputDimension || outHeight > MaxOutputDimension {
+		errs = append(errs, fmt.Errorf("output size %dx%d exceeds %d after upscaling", outWidth, outHeight, MaxOutputDimension))
+	}
+	return errs
+}
+
+// checkUpscaleOptions returns the problems with combining the upscale options
+// for model. A nil upscaleRatio is unset.
+func checkUpscaleOptions(model ModelInfo, highResolution, ultra bool, upscaleRatio *int) []error {
+	var errs []error
+	if highResolution && ultra {
+		errs = append(errs, errors.New("highResolution cannot be combined with ultra"))
+	}
+	if ultra && model.ID != "" && !model.Ultra {
+		errs = append(errs, fmt.Errorf("model %s does not support ultra", modelLabel(model)))
+	}
+	if upscaleRatio != nil && *upscaleRatio < 1 {
+		errs = append(errs, fmt.Errorf("upscaleRatio %d must be at least 1", *upscaleRatio))
+	}
+	return errs
+}
+
+// maxDimension returns the largest width or height model accepts.
+func maxDimension(model ModelInfo) int {
+	if model.MaxDimension > 0 {
+		return model.MaxDimension
+	}
+	return MaxDimension
+}
+
+// outputScale returns how much the upscale options enlarge an image.
+func outputScale(highResolution, ultra bool, upscaleRatio int) float64 {
+	scale := 1.0
+	if highResolution {
+		scale *= HighResolutionScale
+	}
+	if ultra {
+		scale *= UltraScale
+	}
+	if upscaleRatio > 1 {
+		scale *= float64(upscaleRatio)
+	}
+	return scale
+}
+
+func scaleDimensions(width, height int, scale float64) (int, int) {
+	return int(math.Round(float64(width) * scale)), int(math.Round(float64(height) * scale))
+}
+
+// roundDimension rounds v to the nearest multiple of DimensionStep within [MinDimension, limit].
+func roundDimension(v float64, limit int) int {
+	d := int(math.Round(v/DimensionStep)) * DimensionStep
+	if d < MinDimension {
+		return MinDimension
+	}
+	if d > limit {
+		return limit
+	}
+	return d
+}
+
+// modelLabel returns the name of model, or its ID if it has none.
+func modelLabel(model ModelInfo) string {
+	if model.Name != "" {
+		return model.Name
+	}
+	return model.ID
+}
@@ -0,0 +1,99 @@
+package leonardo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPlanDimensions tests planning legal dimensions for aspect ratios and models.
+func TestPlanDimensions(t *testing.T) {
+	phoenix, _ := ModelByName("Leonardo Phoenix")
+	kino, _ := ModelByName("Leonardo Kino XL")
+
+	tests := []struct {
+		name string
+		req  DimensionRequest
+		want Dimensions
+	}{
+		{"model default", DimensionRequest{Model: kino}, Dimensions{1024, 768, 1024, 768}},
+		{"square", DimensionRequest{AspectRatio: 1, Pixels: 512 * 512}, Dimensions{512, 512, 512, 512}},
+		{"wide clamped to max", DimensionRequest{AspectRatio: 16.0 / 9, Pixels: 2000 * 1125, Model: kino}, Dimensions{1024, 576, 1024, 576}},
+		{"high resolution", DimensionRequest{AspectRatio: 1, Pixels: 768 * 768, HighResolution: true}, Dimensions{768, 768, 1152, 1152}},
+		{"ultra", DimensionRequest{Model: phoenix, Ultra: true}, Dimensions{1472, 832, 2944, 1664}},
+		{"upscale limited by output", DimensionRequest{AspectRatio: 1, Pixels: 1024 * 1024, UpscaleRatio: 8}, Dimensions{512, 512, 4096, 4096}},
+		{"tiny", DimensionRequest{AspectRatio: 10, Pixels: 1000}, Dimensions{104, 32, 104, 32}},
+	}
+	for _, tt := range tests {
+		got, err := PlanDimensions(tt.req)
+		if err != nil {
+			t.Errorf("%s: PlanDimensions failed: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: PlanDimensions = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := PlanDimensions(DimensionRequest{Model: kino, Ultra: true}); err == nil {
+		t.Error("Expected error for ultra on a model without ultra support, got nil")
+	}
+	if _, err := PlanDimensions(DimensionRequest{HighResolution: true, Ultra: true}); err == nil {
+		t.Error("Expected error for combining highResolution and ultra, got nil")
+	}
+}
+
+// TestValidateDimensions tests the dimension checks in CreateGenerationRequest.Validate.
+func TestValidateDimensions(t *testing.T) {
+	req := CreateGenerationRequest{Prompt: "A lighthouse.", Width: Ptr(1024), Height: Ptr(768)}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Expected valid request, got %v", err)
+	}
+	if w, h := req.OutputSize(); w != 1024 || h != 768 {
+		t.Errorf("Expected output size 1024x768, got %dx%d", w, h)
+	}
+
+	req.HighResolution = Ptr(true)
+	if w, h := req.OutputSize(); w != 1536 || h != 1152 {
+		t.Errorf("Expected output size 1536x1152, got %dx%d", w, h)
+	}
+
+	req = CreateGenerationRequest{Prompt: "A lighthouse.", Width: Ptr(1500), Height: Ptr(20)}
+	err := req.Validate()
+	if err == nil {
+		t.Fatal("Expected error for out of range dimensions, got nil")
+	}
+	for _, want := range []string{"width 1500 must be between 32 and 1024", "height 20 must be between 32 and 1024"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got %v", want, err)
+		}
+	}
+
+	req = CreateGenerationRequest{Prompt: "A lighthouse.", Width: Ptr(1001)}
+	if err := req.Validate(); err == nil || !strings.Contains(err.Error(), "multiple of 8") {
+		t.Errorf("Expected multiple of 8 error, got %v", err)
+	}
+
+	req = CreateGenerationRequest{Prompt: "A lighthouse.", UpscaleRatio: Ptr(0)}
+	if err := req.Validate(); err == nil || !strings.Contains(err.Error(), "upscaleRatio 0 must be at least 1") {
+		t.Errorf("Expected an upscaleRatio error, got %v", err)
+	}
+
+	phoenix, _ := ModelByName("Leonardo Phoenix")
+	req = CreateGenerationRequest{Prompt: "A lighthouse.", ModelID: Ptr(phoenix.ID), Width: Ptr(1472), Height: Ptr(832), Ultra: Ptr(true), Alchemy: Ptr(false)}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Expected valid Phoenix ultra request, got %v", err)
+	}
+
+	// Unset fields take the API's defaults: a 1024x768 image, with Alchemy on.
+	req = CreateGenerationRequest{Prompt: "A lighthouse.", ModelID: Ptr(phoenix.ID)}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Expected the default size to be valid, got %v", err)
+	}
+	if w, h := req.OutputSize(); w != DefaultWidth || h != DefaultHeight {
+		t.Errorf("Expected output size %dx%d, got %dx%d", DefaultWidth, DefaultHeight, w, h)
+	}
+	req.Ultra = Ptr(true)
+	if err := req.Validate(); err == nil || !strings.Contains(err.Error(), "ultra requires alchemy to be disabled") {
+		t.Errorf("Expected ultra with the default alchemy to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+package leonardo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DryRun intercepts the mutating calls of a Client. When set on Client.DryRun, every
+// request other than a GET (or a pricing calculation) skips the network: its marshalled
+// body is logged, its cost is estimated through the pricing calculator where possible,
+// and a synthetic job handle is returned in place of the API response. Generation
+// requests are validated first and fail as the API would reject them. Realtime canvas
+// and universal upscaler calls return their input image as the result.
+type DryRun struct {
+	// Logger receives one line per intercepted request. Defaults to log.Default().
+	Logger *log.Logger
+
+	mu      sync.Mutex
+	calls   []DryRunCall
+	credits int
+}
+
+// DryRunCall describes a request intercepted by DryRun.
+type DryRunCall struct {
+	Method           string
+	Path             string
+	Body             json.RawMessage
+	JobID            string
+	EstimatedCredits int
+	// EstimateError is set when the cost could not be estimated. It is
+	// ErrUnpriced for requests the pricing calculator has no service for.
+	EstimateError error
+}
+
+// ErrUnpriced is the DryRunCall.EstimateError of requests the pricing calculator cannot price.
+var ErrUnpriced = errors.New("dry run cannot price this request")
+
+// EstimatedCredits returns the total estimated API credit cost of the intercepted calls.
+func (d *DryRun) EstimatedCredits() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.credits
+}
+
+// Calls returns the intercepted calls in the order they were made.
+func (d *DryRun) Calls() []DryRunCall {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DryRunCall(nil), d.calls...)
+}
+
+// Reset forgets the intercepted calls and the accumulated credits.
+func (d *DryRun) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = nil
+	d.credits = 0
+}
+
+func (d *DryRun) logger() *log.Logger {
+	if d.Logger != nil {
+		return d.Logger
+	}
+	return log.Default()
+}
+
+// intercepts reports whether the request would be skipped under the dry run.
+func (d *DryRun) intercepts(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return false
+	}
+	return !strings.HasSuffix(req.URL.Path, "/pricing-calculator")
+}
+
+// do records the request and decodes a synthetic response into v.
+func (d *DryRun) do(c *Client, req *http.Request, v interface{}) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return fmt.Errorf("reading dry run request body failed: %w", err)
+		}
+	}
+
+	path := c.relativePath(req)
+
+	if req.Method == http.MethodPost && path == "/generations" {
+		var genReq CreateGenerationRequest
+		if err := json.Unmarshal(body, &genReq); err != nil {
+			return fmt.Errorf("decoding dry run generation failed: %w", err)
+		}
+		if err := genReq.Validate(); err != nil {
+			return fmt.Errorf("dry run generation is invalid: %w", err)
+		}
+	}
+
+	cost, err := d.estimate(c, req, path, body)
+	if err != nil {
+		d.logger().Printf("leonardo: dry run could not estimate %s %s: %v", req.Method, path, err)
+	}
+
+	d.mu.Lock()
+	jobID := fmt.Sprintf("dry-run-%d", len(d.calls)+1)
+	d.calls = append(d.calls, DryRunCall{
+		Method:           req.Method,
+		Path:             path,
+		Body:             json.RawMessage(body),
+		JobID:            jobID,
+		EstimatedCredits: cost,
+		EstimateError:    err,
+	})
+	d.credits += cost
+	d.mu.Unlock()
+
+	d.logger().Printf("leonardo: dry run %s %s (estimated %d credits) %s", req.Method, path, cost, body)
+
+	if v == nil {
+		return nil
+	}
+	synthetic, err := dryRunResponse(req.Method, path, jobID, cost, body)
+	if err != nil {
+		return fmt.Errorf("decoding dry run request failed: %w", err)
+	}
+	resp, err := json.Marshal(synthetic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp, v)
+}
+
+// freePost reports whether a POST to path costs no credits, as uploads and
+// creating a dataset don't.
+func freePost(path string) bool {
+	switch path {
+	case "/datasets", "/init-image", "/canvas-init-image", "/models-3d/upload":
+		return true
+	}
+	return strings.HasPrefix(path, "/datasets/") && (strings.HasSuffix(path, "/upload") || strings.HasSuffix(path, "/upload/gen"))
+}
+
+// estimate prices the intercepted request through the pricing calculator.
+// Requests that cost nothing are priced at zero; others the calculator cannot
+// price return ErrUnpriced.
+func (d *DryRun) estimate(c *Client, req *http.Request, path string, body []byte) (int, error) {
+	if req.Method != http.MethodPost || freePost(path) {
+		return 0, nil
+	}
+
+	pricing := c.PricingCalculator
+	if pricing == nil {
+		pricing = c.NewPricingCalculatorService()
+	}
+
+	var costReq *CalculateAPICostRequest
+	switch path {
+	case "/generations":
+		var genReq CreateGenerationRequest
+		if err := json.Unmarshal(body, &genReq); err != nil {
+			return 0, err
+		}
+		params, err := pricing.generationParams(req.Context(), genReq)
+		if err != nil {
+			return 0, err
+		}
+		costReq = &CalculateAPICostRequest{
+			Service: PricingServiceTypeImageGeneration,
+			ServiceParams: &PricingServiceParams{
+				ImageGeneration: params,
+			},
+		}
+	case "/models":
+		var trainReq TrainCustomModelRequest
+		if err := json.Unmarshal(body, &trainReq); err != nil {
+			return 0, err
+		}
+		costReq = &CalculateAPICostRequest{
+			Service: PricingServiceTypeModelTraining,
+			ServiceParams: &PricingServiceParams{
+				ModelTraining: &ModelTrainingPricingParams{
+					Resolution: trainReq.Resolution,
+					SDVersion:  trainReq.SDVersion,
+					Strength:   trainReq.Strength,
+				},
+			},
+		}
+	case "/generations-texture":
+		var textureReq CreateTextureGenerationRequest
+		if err := json.Unmarshal(body, &textureReq); err != nil {
+			return 0, err
+		}
+		costReq = &CalculateAPICostRequest{
+			Service: PricingServiceTypeTextureGeneration,
+			ServiceParams: &PricingServiceParams{
+				TextureGeneration: &TextureGenerationPricingParams{
+					Preview: textureReq.Preview,
+				},
+			},
+		}
+	case "/generations-motion-svd":
+		var motionReq struct {
+			DurationSeconds *int `json:"durationSeconds"`
+		}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &motionReq); err != nil {
+				return 0, err
+			}
+		}
+		costReq = &CalculateAPICostRequest{
+			Service: PricingServiceTypeMotionGeneration,
+			ServiceParams: &PricingServiceParams{
+				MotionGeneration: &MotionGenerationPricingParams{
+					DurationSeconds: motionReq.DurationSeconds,
+				},
+			},
+		}
+	case "/variations/universal-upscaler":
+		var upscaleReq UniversalUpscalerRequest
+		if err := json.Unmarshal(body, &upscaleReq); err != nil {
+			return 0, err
+		}
+		params := &UniversalUpscalerPricingParams{}
+		if upscaleReq.ScaleFactor > 0 {
+			params.UpscaleMultiplier = Ptr(upscaleReq.ScaleFactor)
+		}
+		costReq = &CalculateAPICostRequest{
+			Service:       PricingServiceTypeUniversalUpscaler,
+			ServiceParams: &PricingServiceParams{UniversalUpscaler: params},
+		}
+	case "/generations-lcm", "/lcm-instant-refine", "/lcm-inpainting":
+		var lcmReq CreateLCMGenerationRequest
+		if err := json.Unmarshal(body, &lcmReq); err != nil {
+			return 0, err
+		}
+		params := &LCMGenerationPricingParams{
+			Width:          lcmReq.Width,
+			Height:         lcmReq.Height,
+			RefineCreative: lcmReq.RefineCreative,
+		}
+		switch path {
+		case "/lcm-instant-refine":
+			params.InstantRefine = Ptr(true)
+		case "/lcm-inpainting":
+			params.Inpaint = Ptr(true)
+		}
+		costReq = &CalculateAPICostRequest{
+			Service:       PricingServiceTypeLCMGeneration,
+			ServiceParams: &PricingServiceParams{LCMGeneration: params},
+		}
+	default:
+		return 0, ErrUnpriced
+	}
+
+	resp, err := pricing.CalculateAPICost(req.Context(), *costReq)
+	if err != nil {
+		return 0, err
+	}
+	if resp.CalculateProductionApiServiceCost.Cost == nil {
+		return 0, nil
+	}
+	return *resp.CalculateProductionApiServiceCost.Cost, nil
+}
+
+// dryRunResponse builds the synthetic response body for an intercepted request.
+// It fails if a request whose input is echoed back can't be decoded.
+func dryRunResponse(method, path, jobID string, cost int, body []byte) (map[string]interface{}, error) {
+	job := map[string]interface{}{"id": jobID, "apiCreditCost": cost}
+
+	switch method {
+	case http.MethodPost:
+		switch path {
+		case "/generations":
+			return map[string]interface{}{"sdGenerationJob": map[string]interface{}{"generationId": jobID, "apiCreditCost": cost}}, nil
+		case "/models":
+			return map[string]interface{}{"sdTrainingJob": map[string]interface{}{"customModelId": jobID, "apiCreditCost": cost}}, nil
+		case "/datasets":
+			return map[string]interface{}{"insert_datasets_one": map[string]interface{}{"id": jobID}}, nil
+		case "/variations/unzoom":
+			return map[string]interface{}{"sdUnzoomJob": job}, nil
+		case "/variations/upscale":
+			return map[string]interface{}{"sdUpscaleJob": job}, nil
+		case "/variations/nobg":
+			return map[string]interface{}{"sdNobgJob": job}, nil
+		case "/generations-texture":
+			return map[string]interface{}{"textureGenerationJob": job}, nil
+		case "/generations-motion-svd":
+			return map[string]interface{}{"generationId": jobID, "status": GenerationStatusPending}, nil
+		case "/generations-lcm", "/lcm-instant-refine", "/lcm-inpainting", "/lcm-upscale":
+			var lcmReq CreateLCMGenerationRequest
+			if err := json.Unmarshal(body, &lcmReq); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"lcmGenerationJob": map[string]interface{}{
+				"imageDataUrl":     []string{lcmReq.ImageDataURL},
+				"requestTimestamp": lcmReq.RequestTimestamp,
+				"apiCreditCost":    cost,
+			}}, nil
+		case "/variations/universal-upscaler":
+			var upscaleReq UniversalUpscalerRequest
+			if err := json.Unmarshal(body, &upscaleReq); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"upscaled_image_url": upscaleReq.ImageURL}, nil
+		}
+	case http.MethodPut:
+		if id, ok := strings.CutPrefix(path, "/models/"); ok {
+			return map[string]interface{}{"updated_custom_models_by_pk": map[string]interface{}{"id": id}}, nil
+		}
+	case http.MethodDelete:
+		deleted := map[string]string{
+			"/generations/": "delete_generations_by_pk",
+			"/models/":      "delete_custom_models_by_pk",
+			"/datasets/":    "delete_datasets_by_pk",
+			"/init-image/":  "delete_init_images_by_pk",
+			"/models-3d/":   "delete_model_assets_by_pk",
+		}
+		for prefix, key := range deleted {
+			if id, ok := strings.CutPrefix(path, prefix); ok {
+				return map[string]interface{}{key: map[string]interface{}{"id": id}}, nil
+			}
+		}
+	}
+
+	return map[string]interface{}{}, nil
+}
@@ -0,0 +1,171 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image/color"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestDryRunCreateImageGeneration tests that a dry run prices and records a generation without submitting it.
+func TestDryRunCreateImageGeneration(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/pricing-calculator" && r.Method == "POST":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(CalculateAPICostResponse{
+				CalculateProductionApiServiceCost: ServiceCost{
+					Cost: Ptr(24),
+				},
+			})
+		case r.URL.Path == "/generations/gen-123" && r.Method == "GET":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"generations_by_pk":{"id":"gen-123","status":"COMPLETE"}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	var logs bytes.Buffer
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+		DryRun:     &DryRun{Logger: log.New(&logs, "", 0)},
+	}
+	client.Images = client.NewImagesService()
+	client.Models = client.NewModelsService()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		resp, err := client.Images.CreateImageGeneration(ctx, CreateGenerationRequest{
+			Prompt:    "A castle in the clouds.",
+			NumImages: Ptr(2),
+		})
+		if err != nil {
+			t.Fatalf("CreateImageGeneration failed: %v", err)
+		}
+		if resp.SDGenerationJob.GenerationID == nil || !strings.HasPrefix(*resp.SDGenerationJob.GenerationID, "dry-run-") {
+			t.Errorf("Expected synthetic GenerationID, got %v", resp.SDGenerationJob.GenerationID)
+		}
+		if resp.SDGenerationJob.APICreditCost == nil || *resp.SDGenerationJob.APICreditCost != 24 {
+			t.Errorf("Expected APICreditCost 24, got %v", resp.SDGenerationJob.APICreditCost)
+		}
+	}
+
+	// Deletes are intercepted as well
+	del, err := client.Models.DeleteCustomModel(ctx, "model-123")
+	if err != nil {
+		t.Fatalf("DeleteCustomModel failed: %v", err)
+	}
+	if del.DeleteCustomModelsByPK.ID == nil || *del.DeleteCustomModelsByPK.ID != "model-123" {
+		t.Errorf("Expected deleted ID 'model-123', got %v", del.DeleteCustomModelsByPK.ID)
+	}
+
+	// GETs still go through
+	gen, err := client.Images.GetImageGeneration(ctx, "gen-123")
+	if err != nil {
+		t.Fatalf("GetImageGeneration failed: %v", err)
+	}
+	if gen.GenerationsByPK.ID == nil || *gen.GenerationsByPK.ID != "gen-123" {
+		t.Errorf("Expected Generation ID 'gen-123', got %v", gen.GenerationsByPK.ID)
+	}
+
+	if credits := client.DryRun.EstimatedCredits(); credits != 48 {
+		t.Errorf("Expected 48 estimated credits, got %d", credits)
+	}
+	calls := client.DryRun.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 intercepted calls, got %d", len(calls))
+	}
+	if calls[2].Method != "DELETE" || calls[2].Path != "/models/model-123" {
+		t.Errorf("Unexpected third call: %s %s", calls[2].Method, calls[2].Path)
+	}
+	if !strings.Contains(logs.String(), `"prompt":"A castle in the clouds."`) {
+		t.Errorf("Expected logged request body, got %q", logs.String())
+	}
+}
+
+// TestDryRunCoverage tests validating generations, pricing realtime canvas calls and reporting unpriced calls.
+func TestDryRunCoverage(t *testing.T) {
+	var services []PricingServiceType
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pricing-calculator" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req CalculateAPICostRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		services = append(services, req.Service)
+		if req.Service == PricingServiceTypeLCMGeneration && (req.ServiceParams == nil || req.ServiceParams.LCMGeneration == nil || !deref(req.ServiceParams.LCMGeneration.InstantRefine)) {
+			t.Errorf("Expected instant refine LCM params, got %+v", req.ServiceParams)
+		}
+		json.NewEncoder(w).Encode(CalculateAPICostResponse{CalculateProductionApiServiceCost: ServiceCost{Cost: Ptr(2)}})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+		DryRun:     &DryRun{Logger: log.New(io.Discard, "", 0)},
+	}
+	client.Images = client.NewImagesService()
+	client.RealtimeCanvas = client.NewRealtimeCanvasService()
+	client.Variation = client.NewVariationService()
+
+	ctx := context.Background()
+	if _, err := client.Images.CreateImageGeneration(ctx, CreateGenerationRequest{Prompt: "A lighthouse.", Width: Ptr(2000)}); err == nil || !strings.Contains(err.Error(), "width 2000") {
+		t.Errorf("Expected the invalid generation to be rejected, got %v", err)
+	}
+
+	frame, _ := EncodeDataURL(solidImage(color.RGBA{R: 200, A: 255}), "image/png")
+	refined, err := client.RealtimeCanvas.PerformInstantRefine(ctx, PerformInstantRefineRequest{Prompt: "A lighthouse.", ImageDataURL: frame, RequestTimestamp: Ptr("42")})
+	if err != nil {
+		t.Fatalf("PerformInstantRefine failed: %v", err)
+	}
+	job := refined.LCMGenerationJob
+	if job == nil || len(job.ImageDataURL) != 1 || deref(job.RequestTimestamp) != "42" || deref(job.APICreditCost) != 2 {
+		t.Fatalf("Unexpected synthetic job: %+v", job)
+	}
+	if _, err := DecodeDataURL(job.ImageDataURL[0]); err != nil {
+		t.Errorf("Expected a decodable image, got %v", err)
+	}
+
+	if _, err := client.Variation.CreateUnzoomVariation(ctx, VariationRequest{ID: "img-1"}); err != nil {
+		t.Fatalf("CreateUnzoomVariation failed: %v", err)
+	}
+	calls := client.DryRun.Calls()
+	if len(calls) != 2 || calls[0].EstimateError != nil || !errors.Is(calls[1].EstimateError, ErrUnpriced) {
+		t.Errorf("Expected the unzoom to be reported unpriced, got %+v", calls)
+	}
+	if len(services) != 1 || services[0] != PricingServiceTypeLCMGeneration {
+		t.Errorf("Expected one LCM pricing request, got %v", services)
+	}
+}
+
+// TestDryRunResponseDecodeError tests that a request whose input is echoed back must decode.
+func TestDryRunResponseDecodeError(t *testing.T) {
+	for _, path := range []string{"/generations-lcm", "/variations/universal-upscaler"} {
+		if _, err := dryRunResponse(http.MethodPost, path, "dry-run-1", 0, []byte(`{"imageDataUrl": 42, "image_url": 42}`)); err == nil {
+			t.Errorf("%s: Expected a decoding error, got nil", path)
+		}
+	}
+}
@@ -21,12 +21,13 @@ func (s *ElementsService) ListElements(ctx context.Context) (*ListElementsRespon
 	var resp ListElementsResponse
 	path := "/elements"
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating ListElements request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Elements",
+		Operation: "ListElements",
+		Method:    "GET",
+		Path:      path,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("listing elements failed: %w", err)
 	}
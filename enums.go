@@ -0,0 +1,170 @@
+package leonardo
+
+import "strings"
+
+// The string enums in this package implement encoding.TextUnmarshaler so that
+// values differing only in case are normalized to the known constant. Values the
+// package doesn't know about are kept as-is rather than rejected, so a response
+// using a value added to the API later still decodes; IsValid reports whether a
+// value is one of the known constants.
+
+var (
+	presetStyles = []PresetStyle{
+		PresetStyleNone, PresetStyleLeonardo, PresetStyleAnime, PresetStyleCreative,
+		PresetStyleDynamic, PresetStyleEnvironment, PresetStyleGeneral, PresetStyleIllustration,
+		PresetStylePhotography, PresetStyleRaytraced, PresetStyleRender3D, PresetStyleSketchBW,
+		PresetStyleSketchColor, PresetStyleStockPhoto, PresetStyleVibrant, PresetStyleUnprocessed,
+		PresetStyleBokeh, PresetStyleCinematic, PresetStyleCinematicCloseup, PresetStyleFashion,
+		PresetStyleFilm, PresetStyleFood, PresetStyleHDR, PresetStyleLongExposure,
+		PresetStyleMacro, PresetStyleMinimalistic, PresetStyleMonochrome, PresetStyleMoody,
+		PresetStyleNeutral, PresetStylePortrait, PresetStyleRetro,
+	}
+	schedulers = []Scheduler{
+		SchedulerKLMS, SchedulerEulerAncestralDiscrete, SchedulerEulerDiscrete, SchedulerDDIM,
+		SchedulerDPMSolver, SchedulerPNDM, SchedulerLeonardo,
+	}
+	sdVersions = []SDVersion{
+		SDVersionV1_5, SDVersionV2, SDVersionV3, SDVersionSDXL_0_8, SDVersionSDXL_0_9,
+		SDVersionSDXL_1_0, SDVersionSDXL_LIGHTNING, SDVersionPhoenix,
+	}
+	canvasRequestTypes = []CanvasRequestType{
+		CanvasRequestTypeInpaint, CanvasRequestTypeOutpaint, CanvasRequestTypeSketch2Img, CanvasRequestTypeImg2Img,
+	}
+	photoRealVersions   = []PhotoRealVersion{PhotoRealVersionV1, PhotoRealVersionV2}
+	promptMagicVersions = []PromptMagicVersion{PromptMagicVersionV2, PromptMagicVersionV3}
+	transparencies      = []Transparency{TransparencyDisabled, TransparencyForegroundOnly}
+	generationStatuses  = []GenerationStatus{GenerationStatusComplete, GenerationStatusFailed, GenerationStatusPending}
+	transformTypes      = []TransformType{
+		TransformTypeOutpaint, TransformTypeInpaint, TransformTypeUpscale, TransformTypeUnzoom, TransformTypeNoBackground,
+	}
+	customModelTypes = []CustomModelType{
+		CustomModelTypeGeneral, CustomModelTypeBuildings, CustomModelTypeCharacters, CustomModelTypeEnvironments,
+		CustomModelTypeFashion, CustomModelTypeIllustrations, CustomModelTypeGameItems, CustomModelTypeGraphicalElements,
+		CustomModelTypePhotography, CustomModelTypePixelArt, CustomModelTypeProductDesign, CustomModelTypeTextures,
+		CustomModelTypeUIElements, CustomModelTypeVector,
+	}
+	trainingStrengths = []TrainingStrength{
+		TrainingStrengthVeryLow, TrainingStrengthLow, TrainingStrengthMedium, TrainingStrengthHigh,
+	}
+	previewDirections = []PreviewDirection{
+		PreviewDirectionFront, PreviewDirectionBack, PreviewDirectionLeft, PreviewDirectionRight,
+	}
+)
+
+// knownEnum reports whether v is one of known.
+func knownEnum[T ~string](v T, known []T) bool {
+	for _, k := range known {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEnum returns the known value matching text case-insensitively, or text
+// itself if there is none.
+func parseEnum[T ~string](text []byte, known []T) T {
+	s := strings.TrimSpace(string(text))
+	for _, k := range known {
+		if strings.EqualFold(s, string(k)) {
+			return k
+		}
+	}
+	return T(s)
+}
+
+// IsValid reports whether s is a known preset style.
+func (s PresetStyle) IsValid() bool { return knownEnum(s, presetStyles) }
+
+func (s *PresetStyle) UnmarshalText(text []byte) error {
+	*s = parseEnum(text, presetStyles)
+	return nil
+}
+
+// IsValid reports whether s is a known scheduler.
+func (s Scheduler) IsValid() bool { return knownEnum(s, schedulers) }
+
+func (s *Scheduler) UnmarshalText(text []byte) error {
+	*s = parseEnum(text, schedulers)
+	return nil
+}
+
+// IsValid reports whether v is a known Stable Diffusion version.
+func (v SDVersion) IsValid() bool { return knownEnum(v, sdVersions) }
+
+func (v *SDVersion) UnmarshalText(text []byte) error {
+	*v = parseEnum(text, sdVersions)
+	return nil
+}
+
+// IsValid reports whether t is a known canvas request type.
+func (t CanvasRequestType) IsValid() bool { return knownEnum(t, canvasRequestTypes) }
+
+func (t *CanvasRequestType) UnmarshalText(text []byte) error {
+	*t = parseEnum(text, canvasRequestTypes)
+	return nil
+}
+
+// IsValid reports whether v is a known PhotoReal version.
+func (v PhotoRealVersion) IsValid() bool { return knownEnum(v, photoRealVersions) }
+
+func (v *PhotoRealVersion) UnmarshalText(text []byte) error {
+	*v = parseEnum(text, photoRealVersions)
+	return nil
+}
+
+// IsValid reports whether v is a known Prompt Magic version.
+func (v PromptMagicVersion) IsValid() bool { return knownEnum(v, promptMagicVersions) }
+
+func (v *PromptMagicVersion) UnmarshalText(text []byte) error {
+	*v = parseEnum(text, promptMagicVersions)
+	return nil
+}
+
+// IsValid reports whether t is a known transparency mode.
+func (t Transparency) IsValid() bool { return knownEnum(t, transparencies) }
+
+func (t *Transparency) UnmarshalText(text []byte) error {
+	*t = parseEnum(text, transparencies)
+	return nil
+}
+
+// IsValid reports whether s is a known generation status.
+func (s GenerationStatus) IsValid() bool { return knownEnum(s, generationStatuses) }
+
+func (s *GenerationStatus) UnmarshalText(text []byte) error {
+	*s = parseEnum(text, generationStatuses)
+	return nil
+}
+
+// IsValid reports whether t is a known variation transform type.
+func (t TransformType) IsValid() bool { return knownEnum(t, transformTypes) }
+
+func (t *TransformType) UnmarshalText(text []byte) error {
+	*t = parseEnum(text, transformTypes)
+	return nil
+}
+
+// IsValid reports whether t is a known custom model type.
+func (t CustomModelType) IsValid() bool { return knownEnum(t, customModelTypes) }
+
+func (t *CustomModelType) UnmarshalText(text []byte) error {
+	*t = parseEnum(text, customModelTypes)
+	return nil
+}
+
+// IsValid reports whether s is a known training strength.
+func (s TrainingStrength) IsValid() bool { return knownEnum(s, trainingStrengths) }
+
+func (s *TrainingStrength) UnmarshalText(text []byte) error {
+	*s = parseEnum(text, trainingStrengths)
+	return nil
+}
+
+// IsValid reports whether d is a known preview direction.
+func (d PreviewDirection) IsValid() bool { return knownEnum(d, previewDirections) }
+
+func (d *PreviewDirection) UnmarshalText(text []byte) error {
+	*d = parseEnum(text, previewDirections)
+	return nil
+}
@@ -0,0 +1,44 @@
+package leonardo
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestEnums tests IsValid and tolerant decoding of the string enums.
+func TestEnums(t *testing.T) {
+	if !PhotoRealVersionV2.IsValid() || PhotoRealVersion("v9").IsValid() {
+		t.Error("Unexpected PhotoRealVersion validity")
+	}
+	if !TrainingStrengthVeryLow.IsValid() || !CustomModelTypePixelArt.IsValid() || !PreviewDirectionFront.IsValid() {
+		t.Error("Expected known training and texture values to be valid")
+	}
+
+	var req CreateGenerationRequest
+	data := `{"prompt": "A lighthouse.", "photoRealVersion": "V2", "promptMagicVersion": "v4", "transparency": "foreground_only"}`
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if *req.PhotoRealVersion != PhotoRealVersionV2 {
+		t.Errorf("Expected PhotoRealVersion normalized to v2, got %q", *req.PhotoRealVersion)
+	}
+	if *req.PromptMagicVersion != "v4" || req.PromptMagicVersion.IsValid() {
+		t.Errorf("Expected unknown PromptMagicVersion v4 to be kept and invalid, got %q", *req.PromptMagicVersion)
+	}
+	if *req.Transparency != TransparencyForegroundOnly {
+		t.Errorf("Expected Transparency foreground_only, got %q", *req.Transparency)
+	}
+
+	var variation GetVariationResponse
+	data = `{"generated_image_variation_generic": [{"id": "var-1", "status": "complete", "transformType": "REMIX"}]}`
+	if err := json.Unmarshal([]byte(data), &variation); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	v := variation.Variation()
+	if *v.Status != GenerationStatusComplete {
+		t.Errorf("Expected status COMPLETE, got %q", *v.Status)
+	}
+	if *v.TransformType != "REMIX" || v.TransformType.IsValid() {
+		t.Errorf("Expected unknown transform type REMIX to be kept and invalid, got %q", *v.TransformType)
+	}
+}
@@ -41,7 +41,7 @@ func main() {
 		fmt.Printf("API Paid Tokens: %d\n", *userDetails.APIPaidTokens)
 	}
 	if userDetails.APIPlanTokenRenewalDate != nil {
-		fmt.Printf("API Plan Token Renewal Date: %s\n", *userDetails.APIPlanTokenRenewalDate)
+		fmt.Printf("API Plan Token Renewal Date: %s\n", userDetails.APIPlanTokenRenewalDate)
 	}
 	if userDetails.APIConcurrencySlots != nil {
 		fmt.Printf("API Concurrency Slots: %d\n", *userDetails.APIConcurrencySlots)
@@ -62,7 +62,7 @@ func main() {
 		fmt.Printf("Subscription Tokens: %d\n", *userDetails.SubscriptionTokens)
 	}
 	if userDetails.TokenRenewalDate != nil {
-		fmt.Printf("Token Renewal Date: %s\n", *userDetails.TokenRenewalDate)
+		fmt.Printf("Token Renewal Date: %s\n", userDetails.TokenRenewalDate)
 	}
 
 	// Generate a prompt
@@ -8,3 +8,12 @@ import (
 func urlPathEscape(s string) string {
 	return url.PathEscape(s)
 }
+
+// deref returns the value v points to, or the zero value if v is nil.
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
@@ -3,8 +3,10 @@ package leonardo
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // ImagesService provides methods to interact with the Image endpoints of the Leonardo.ai API.
@@ -23,12 +25,14 @@ func (s *ImagesService) CreateImageGeneration(ctx context.Context, req CreateGen
 	var resp CreateGenerationResponse
 	path := "/generations"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateImageGeneration request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Images",
+		Operation: "CreateImageGeneration",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating image generation failed: %w", err)
 	}
@@ -42,12 +46,14 @@ func (s *ImagesService) GetImageGeneration(ctx context.Context, id string) (*Get
 	var resp GetGenerationResponse
 	path := fmt.Sprintf("/generations/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating GetImageGeneration request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Images",
+		Operation: "GetImageGeneration",
+		Method:    http.MethodGet,
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("getting image generation failed: %w", err)
 	}
@@ -61,12 +67,14 @@ func (s *ImagesService) DeleteGeneration(ctx context.Context, id string) (*Delet
 	var resp DeleteGenerationResponse
 	path := fmt.Sprintf("/generations/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating DeleteGeneration request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Images",
+		Operation: "DeleteGeneration",
+		Method:    http.MethodDelete,
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("deleting generation failed: %w", err)
 	}
@@ -95,15 +103,108 @@ func (s *ImagesService) GetGenerationsByUserID(ctx context.Context, userID strin
 
 	path := fmt.Sprintf("/generations/user/%s%s", url.PathEscape(userID), queryString)
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Images",
+		Operation: "GetGenerationsByUserID",
+		Method:    "GET",
+		Path:      path,
+		ID:        userID,
+		Response:  &resp,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("creating GetGenerationsByUserID request failed: %w", err)
+		return nil, fmt.Errorf("retrieving generations by user ID failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// DefaultPollInterval is the interval WaitForGeneration polls at when none is given.
+const DefaultPollInterval = 2 * time.Second
+
+// WaitForGeneration polls GetImageGeneration until the generation is no longer
+// PENDING or ctx is done, and returns the last response. An interval of zero
+// uses DefaultPollInterval. A FAILED generation is returned with an error.
+func (s *ImagesService) WaitForGeneration(ctx context.Context, id string, interval time.Duration) (resp *GetGenerationResponse, err error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
 	}
 
-	err = s.client.Do(httpReq, &resp)
+	ctx, span := s.client.startSpan(ctx, "Images.WaitForGeneration", Attr("leonardo.id", id))
+	polls := 0
+	defer func() {
+		span.SetAttributes(Attr("leonardo.polls", polls))
+		endSpan(span, err)
+	}()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-timer.C:
+		}
+
+		polls++
+		resp, err = s.GetImageGeneration(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		status := resp.GenerationsByPK.Status
+		if status != nil && *status != GenerationStatusPending {
+			span.SetAttributes(Attr("leonardo.status", string(*status)))
+			if *status == GenerationStatusFailed {
+				return resp, fmt.Errorf("generation %s failed", id)
+			}
+			return resp, nil
+		}
+		timer.Reset(interval)
+	}
+}
+
+// DownloadImage downloads the image at url, such as a generated image URL, into w.
+func (s *ImagesService) DownloadImage(ctx context.Context, url string, w io.Writer, opts ...DownloadOption) (n int64, err error) {
+	var o downloadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, span := s.client.startSpan(ctx, "Images.DownloadImage", Attr("http.url", url))
+	defer func() {
+		span.SetAttributes(Attr("leonardo.bytes", n))
+		endSpan(span, err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("retrieving generations by user ID failed: %w", err)
+		return 0, fmt.Errorf("creating DownloadImage request failed: %w", err)
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("downloading image failed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	return &resp, nil
+	span.SetAttributes(Attr("http.status_code", resp.StatusCode))
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("downloading image failed with status code: %d", resp.StatusCode)
+	}
+
+	if o.metadata != nil {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, fmt.Errorf("downloading image failed: %w", err)
+		}
+		if data, err = EmbedImageMetadata(data, *o.metadata); err != nil {
+			return 0, err
+		}
+		written, err := w.Write(data)
+		return int64(written), err
+	}
+
+	n, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("downloading image failed: %w", err)
+	}
+	return n, nil
 }
@@ -39,10 +39,7 @@ func TestCreateImageGeneration(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		resp := CreateGenerationResponse{
-			SDGenerationJob: struct {
-				APICreditCost *int    `json:"apiCreditCost"`
-				GenerationID  *string `json:"generationId"`
-			}{
+			SDGenerationJob: GenerationJob{
 				APICreditCost: Ptr(2),
 				GenerationID:  Ptr("gen-123"),
 			},
@@ -101,15 +98,10 @@ func TestGetImageGeneration(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		resp := GetGenerationResponse{
-			GenerationsByPK: struct {
-				ID        *string `json:"id"`
-				Status    *string `json:"status"`
-				CreatedAt *Time   `json:"createdAt"`
-				// Add other fields as necessary
-			}{
+			GenerationsByPK: GenerationDetails{
 				ID:        Ptr("gen-123"),
-				Status:    Ptr("COMPLETE"),
-				CreatedAt: &Time{time.Now().Add(-2 * time.Hour)},
+				Status:    Ptr(GenerationStatusComplete),
+				CreatedAt: &Time{Time: time.Now().Add(-2 * time.Hour)},
 			},
 		}
 		json.NewEncoder(w).Encode(resp)
@@ -137,7 +129,7 @@ func TestGetImageGeneration(t *testing.T) {
 	if resp.GenerationsByPK.ID == nil || *resp.GenerationsByPK.ID != "gen-123" {
 		t.Errorf("Expected Generation ID 'gen-123', got '%v'", resp.GenerationsByPK.ID)
 	}
-	if resp.GenerationsByPK.Status == nil || *resp.GenerationsByPK.Status != "COMPLETE" {
+	if resp.GenerationsByPK.Status == nil || *resp.GenerationsByPK.Status != GenerationStatusComplete {
 		t.Errorf("Expected Status 'COMPLETE', got '%v'", resp.GenerationsByPK.Status)
 	}
 	if resp.GenerationsByPK.CreatedAt == nil {
@@ -169,9 +161,7 @@ func TestDeleteGeneration(t *testing.T) {
 		// Mock successful deletion response
 		w.Header().Set("Content-Type", "application/json")
 		response := DeleteGenerationResponse{
-			DeleteGenerationsByPK: struct {
-				ID *string `json:"id"`
-			}{
+			DeleteGenerationsByPK: ResourceID{
 				ID: Ptr("gen-123"),
 			},
 		}
@@ -18,22 +18,18 @@ func (c *Client) NewInitImagesService() *InitImagesService {
 
 // UploadInitImage uploads an init image and retrieves presigned S3 upload details.
 // POST /init-image
-func (s *InitImagesService) UploadInitImage(ctx context.Context, req UploadInitImageRequest) (*struct {
-	UploadInitImageID *string `json:"uploadInitImageId"`
-	Message           string  `json:"message"`
-}, error) {
-	var resp struct {
-		UploadInitImageID *string `json:"uploadInitImageId"`
-		Message           string  `json:"message"`
-	}
+func (s *InitImagesService) UploadInitImage(ctx context.Context, req UploadInitImageRequest) (*UploadInitImageResponse, error) {
+	var resp UploadInitImageResponse
 	path := "/init-image"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating UploadInitImage request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "InitImages",
+		Operation: "UploadInitImage",
+		Method:    "POST",
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("uploading init image failed: %w", err)
 	}
@@ -47,12 +43,14 @@ func (s *InitImagesService) GetSingleInitImage(ctx context.Context, id string) (
 	var resp GetSingleInitImageResponse
 	path := fmt.Sprintf("/init-image/%s", url.PathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating GetSingleInitImage request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "InitImages",
+		Operation: "GetSingleInitImage",
+		Method:    "GET",
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving init image failed: %w", err)
 	}
@@ -66,12 +64,14 @@ func (s *InitImagesService) DeleteInitImage(ctx context.Context, id string) (*De
 	var resp DeleteInitImageResponse
 	path := fmt.Sprintf("/init-image/%s", url.PathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, "DELETE", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating DeleteInitImage request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "InitImages",
+		Operation: "DeleteInitImage",
+		Method:    "DELETE",
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("deleting init image failed: %w", err)
 	}
@@ -85,12 +85,14 @@ func (s *InitImagesService) UploadCanvasInitAndMaskImage(ctx context.Context, re
 	var resp UploadCanvasInitAndMaskImageResponse
 	path := "/canvas-init-image"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating UploadCanvasInitAndMaskImage request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "InitImages",
+		Operation: "UploadCanvasInitAndMaskImage",
+		Method:    "POST",
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("uploading canvas init and mask images failed: %w", err)
 	}
@@ -83,12 +83,8 @@ func TestGetSingleInitImage(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := GetSingleInitImageResponse{
-			InitImagesByPk: struct {
-				CreatedAt *Time   `json:"createdAt"`
-				ID        *string `json:"id"`
-				URL       *string `json:"url"`
-			}{
-				CreatedAt: &Time{time.Now().Add(-2 * time.Hour)},
+			InitImagesByPk: InitImage{
+				CreatedAt: &Time{Time: time.Now().Add(-2 * time.Hour)},
 				ID:        Ptr(expectedID),
 				URL:       Ptr("https://s3.amazonaws.com/bucket/init-001.jpg"),
 			},
@@ -139,9 +135,7 @@ func TestDeleteInitImage(t *testing.T) {
 		// Mock successful deletion response
 		w.Header().Set("Content-Type", "application/json")
 		response := DeleteInitImageResponse{
-			DeleteInitImagesByPk: struct {
-				ID *string `json:"id"`
-			}{
+			DeleteInitImagesByPk: ResourceID{
 				ID: Ptr(expectedID),
 			},
 		}
@@ -193,16 +187,7 @@ func TestUploadCanvasInitAndMaskImage(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := UploadCanvasInitAndMaskImageResponse{
-			UploadCanvasInitImage: &struct {
-				InitFields  string `json:"initFields"`
-				InitImageID string `json:"initImageId"`
-				InitKey     string `json:"initKey"`
-				InitURL     string `json:"initUrl"`
-				MaskFields  string `json:"maskFields"`
-				MaskImageID string `json:"maskImageId"`
-				MaskKey     string `json:"maskKey"`
-				MaskURL     string `json:"maskUrl"`
-			}{
+			UploadCanvasInitImage: &CanvasInitImageUpload{
 				InitFields:  "init-policy",
 				InitImageID: "init-img-001",
 				InitKey:     "uploads/init-img-001.jpg",
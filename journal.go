@@ -0,0 +1,393 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JournalEvent identifies what a JournalEntry records.
+type JournalEvent string
+
+const (
+	JournalSubmitted  JournalEvent = "submitted"
+	JournalCompleted  JournalEvent = "completed"
+	JournalFailed     JournalEvent = "failed"
+	JournalDownloaded JournalEvent = "downloaded"
+)
+
+// JournalEntry is a single record in a Journal.
+type JournalEntry struct {
+	Event     JournalEvent    `json:"event"`
+	JobID     string          `json:"jobId"`
+	Time      Time            `json:"time"`
+	Kind      JobKind         `json:"kind,omitempty"`
+	Operation string          `json:"operation,omitempty"`
+	Request   json.RawMessage `json:"request,omitempty"`
+	Cost      int             `json:"cost,omitempty"`
+	Files     []string        `json:"files,omitempty"`
+}
+
+// Journal is an append-only record of submitted jobs.
+type Journal interface {
+	// Append durably records entry.
+	Append(ctx context.Context, entry JournalEntry) error
+	// Entries returns every entry in the order it was appended.
+	Entries(ctx context.Context) ([]JournalEntry, error)
+}
+
+// FileJournal is a Journal stored as JSON lines in a file. Every entry is
+// synced to disk before Append returns.
+type FileJournal struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// OpenJournal opens the journal file at path, creating it if needed. A
+// partial last line, left by a crash while appending, is discarded.
+func OpenJournal(path string) (*FileJournal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("opening journal failed: %w", err)
+	}
+	if n := len(data); n > 0 && data[n-1] != '\n' {
+		if err := os.Truncate(path, int64(bytes.LastIndexByte(data, '\n')+1)); err != nil {
+			return nil, fmt.Errorf("repairing journal failed: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening journal failed: %w", err)
+	}
+	return &FileJournal{path: path, file: f}, nil
+}
+
+// Append implements Journal.
+func (j *FileJournal) Append(ctx context.Context, entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding journal entry failed: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return errors.New("writing journal entry failed: journal is closed")
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing journal entry failed: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("writing journal entry failed: %w", err)
+	}
+	return nil
+}
+
+// Entries implements Journal.
+func (j *FileJournal) Entries(ctx context.Context) ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading journal failed: %w", err)
+	}
+	var entries []JournalEntry
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("decoding journal line %d failed: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Close closes the journal file.
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// jobKinds maps the operations that submit jobs to the kind of job they submit.
+var jobKinds = map[string]JobKind{
+	"Images.CreateImageGeneration":          JobGeneration,
+	"Motion.CreateSVDMotionGeneration":      JobGeneration,
+	"Texture.CreateTextureGeneration":       JobTexture,
+	"Variation.CreateUpscaleVariation":      JobVariation,
+	"Variation.CreateUnzoomVariation":       JobVariation,
+	"Variation.CreateNoBackgroundVariation": JobVariation,
+	"Models.TrainCustomModel":               JobTraining,
+}
+
+// JournalMiddleware records every job submitted through the client in j, with
+// its request and cost, and records when a submitted job is seen to complete
+// or fail. If a submission cannot be recorded the call returns an error
+// naming the job, so that no paid-for job goes untracked silently. Calls
+// intercepted by Client.DryRun are not journaled.
+func JournalMiddleware(j Journal) Middleware {
+	var mu sync.Mutex
+	var open map[string]bool // jobs submitted and not yet seen to finish
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if err := next(ctx, call); err != nil {
+				return err
+			}
+			if call.intercepted {
+				return nil
+			}
+			id, status, ok := responseJobStatus(call.Response)
+			if !ok {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if open == nil {
+				jobs, err := IncompleteJobs(ctx, j)
+				if err != nil {
+					return fmt.Errorf("journaling job %s failed: %w", id, err)
+				}
+				open = map[string]bool{}
+				for _, job := range jobs {
+					if job.Status == GenerationStatusPending {
+						open[job.ID] = true
+					}
+				}
+			}
+
+			entry := JournalEntry{JobID: id, Time: Time{Time: time.Now().UTC()}}
+			switch kind, submits := jobKinds[call.Name()]; {
+			case submits && status == GenerationStatusPending:
+				request, err := json.Marshal(call.Request)
+				if err != nil {
+					return fmt.Errorf("journaling job %s failed: %w", id, err)
+				}
+				entry.Event, entry.Kind, entry.Operation = JournalSubmitted, kind, call.Name()
+				entry.Request, entry.Cost = request, responseCreditCost(call.Response)
+			case open[id] && status == GenerationStatusComplete:
+				entry.Event = JournalCompleted
+			case open[id] && status == GenerationStatusFailed:
+				entry.Event = JournalFailed
+			default:
+				return nil
+			}
+			if err := j.Append(ctx, entry); err != nil {
+				return fmt.Errorf("journaling job %s failed: %w", id, err)
+			}
+			if entry.Event == JournalSubmitted {
+				open[id] = true
+			} else {
+				delete(open, id)
+			}
+			return nil
+		}
+	}
+}
+
+// JournaledJob is the state of a job reconstructed from a Journal.
+type JournaledJob struct {
+	ID          string
+	Kind        JobKind
+	Operation   string
+	Request     json.RawMessage
+	Cost        int
+	SubmittedAt Time
+	// Status is PENDING until the job is seen to complete or fail.
+	Status GenerationStatus
+	// Downloaded reports whether the outputs were downloaded, into Files.
+	Downloaded bool
+	Files      []string
+}
+
+// Jobs replays j and returns every job it records, in submission order.
+func Jobs(ctx context.Context, j Journal) ([]*JournaledJob, error) {
+	entries, err := j.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*JournaledJob
+	byID := map[string]*JournaledJob{}
+	for _, e := range entries {
+		job, ok := byID[e.JobID]
+		if e.Event == JournalSubmitted {
+			if !ok {
+				job = &JournaledJob{ID: e.JobID}
+				byID[e.JobID] = job
+				jobs = append(jobs, job)
+			}
+			job.Kind, job.Operation, job.Request, job.Cost = e.Kind, e.Operation, e.Request, e.Cost
+			job.SubmittedAt, job.Status = e.Time, GenerationStatusPending
+			continue
+		}
+		if !ok {
+			continue
+		}
+		switch e.Event {
+		case JournalCompleted:
+			job.Status = GenerationStatusComplete
+		case JournalFailed:
+			job.Status = GenerationStatusFailed
+		case JournalDownloaded:
+			job.Status, job.Downloaded, job.Files = GenerationStatusComplete, true, e.Files
+		}
+	}
+	return jobs, nil
+}
+
+// IncompleteJobs returns the journaled jobs still needing attention: those
+// not yet seen to finish, and finished generations and variations whose
+// outputs haven't been downloaded. Failed jobs and completed training jobs
+// are done.
+func IncompleteJobs(ctx context.Context, j Journal) ([]*JournaledJob, error) {
+	jobs, err := Jobs(ctx, j)
+	if err != nil {
+		return nil, err
+	}
+	incomplete := jobs[:0]
+	for _, job := range jobs {
+		switch {
+		case job.Status == GenerationStatusFailed:
+		case job.Status == GenerationStatusComplete && (job.Kind == JobTraining || job.Downloaded):
+		default:
+			incomplete = append(incomplete, job)
+		}
+	}
+	return incomplete, nil
+}
+
+// ResumeJobs finishes the incomplete jobs in j: it waits for each generation,
+// motion, texture and variation, downloads its outputs into dir and records
+// the download. A job is journaled as failed only once the API reports it
+// FAILED; if ctx ends or polling fails it stays incomplete. Training jobs have
+// nothing to download and are left to the caller. Every job is attempted; the
+// errors are joined.
+func (c *Client) ResumeJobs(ctx context.Context, j Journal, dir string, interval time.Duration) error {
+	jobs, err := IncompleteJobs(ctx, j)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, job := range jobs {
+		if err := c.resumeJob(ctx, j, job, dir, interval); err != nil {
+			errs = append(errs, fmt.Errorf("resuming job %s failed: %w", job.ID, err))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resumeJob waits for a single job and downloads its outputs.
+func (c *Client) resumeJob(ctx context.Context, j Journal, job *JournaledJob, dir string, interval time.Duration) error {
+	var urls []string
+	ext := ".jpg"
+	switch job.Kind {
+	case JobTraining:
+		return nil
+	case JobVariation:
+		resp, err := c.Variation.WaitForVariation(ctx, job.ID, interval)
+		var v *Variation
+		if resp != nil {
+			v = resp.Variation()
+		}
+		if err != nil {
+			return c.recordFailure(ctx, j, job, v != nil && deref(v.Status) == GenerationStatusFailed, err)
+		}
+		if v != nil && v.URL != nil {
+			urls = append(urls, *v.URL)
+		}
+	default:
+		resp, err := c.Images.WaitForGeneration(ctx, job.ID, interval)
+		if err != nil {
+			return c.recordFailure(ctx, j, job, resp != nil && resp.Status() == GenerationStatusFailed, err)
+		}
+		motion := job.Operation == "Motion.CreateSVDMotionGeneration"
+		if motion {
+			ext = ".mp4"
+		}
+		for _, img := range resp.Images() {
+			u := img.URL
+			if motion {
+				u = img.MotionMP4URL
+			}
+			if u != nil {
+				urls = append(urls, *u)
+			}
+		}
+	}
+
+	var files []string
+	for i, u := range urls {
+		name := filepath.Join(dir, fmt.Sprintf("%s-%d%s", job.ID, i, fileExt(u, ext)))
+		if err := c.downloadFile(ctx, u, name); err != nil {
+			return err
+		}
+		files = append(files, name)
+	}
+	return j.Append(ctx, JournalEntry{Event: JournalDownloaded, JobID: job.ID, Time: Time{Time: time.Now().UTC()}, Files: files})
+}
+
+// recordFailure journals the job as failed if the API reported it FAILED, then returns err.
+func (c *Client) recordFailure(ctx context.Context, j Journal, job *JournaledJob, failed bool, err error) error {
+	if failed {
+		if jerr := j.Append(ctx, JournalEntry{Event: JournalFailed, JobID: job.ID, Time: Time{Time: time.Now().UTC()}}); jerr != nil {
+			return errors.Join(err, jerr)
+		}
+	}
+	return err
+}
+
+// downloadFile downloads url into the file name, replacing it only once the
+// download succeeds.
+func (c *Client) downloadFile(ctx context.Context, url, name string) error {
+	f, err := os.CreateTemp(filepath.Dir(name), ".download-*")
+	if err != nil {
+		return fmt.Errorf("creating download file failed: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := c.Images.DownloadImage(ctx, url, f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing download file failed: %w", err)
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// fileExt returns the file extension of a URL, defaulting to def.
+func fileExt(rawURL, def string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return def
+	}
+	if ext := path.Ext(u.Path); ext != "" {
+		return ext
+	}
+	return def
+}
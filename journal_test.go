@@ -0,0 +1,292 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestJournalResume tests journaling a submission and resuming it after a restart.
+func TestJournalResume(t *testing.T) {
+	// Mock server setup
+	var server *httptest.Server
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /generations":
+			json.NewEncoder(w).Encode(CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr("gen-1"), APICreditCost: Ptr(5)}})
+		case "GET /generations/gen-1":
+			json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{
+				ID:              Ptr("gen-1"),
+				Status:          Ptr(GenerationStatusComplete),
+				GeneratedImages: []GeneratedImage{{ID: Ptr("img-1"), URL: Ptr(server.URL + "/images/img-1.png?v=1")}},
+			}})
+		case "GET /images/img-1.png":
+			w.Write([]byte("png data"))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server = httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	newClient := func(j Journal) *Client {
+		client := &Client{
+			BaseURL:    server.URL,
+			HTTPClient: server.Client(),
+			APIKey:     "test-api-key",
+		}
+		client.Images = client.NewImagesService()
+		client.Use(JournalMiddleware(j))
+		return client
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "jobs.jsonl")
+	journal, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	if _, err := newClient(journal).Images.CreateImageGeneration(ctx, CreateGenerationRequest{Prompt: "A lighthouse."}); err != nil {
+		t.Fatalf("CreateImageGeneration failed: %v", err)
+	}
+	journal.Close()
+
+	// Simulate a crash part way through appending.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.Write([]byte(`{"event":"comp`))
+	f.Close()
+
+	journal, err = OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	defer journal.Close()
+
+	jobs, err := IncompleteJobs(ctx, journal)
+	if err != nil {
+		t.Fatalf("IncompleteJobs failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "gen-1" || jobs[0].Kind != JobGeneration || jobs[0].Cost != 5 || jobs[0].Status != GenerationStatusPending {
+		t.Fatalf("Unexpected incomplete jobs: %+v", jobs)
+	}
+	var req CreateGenerationRequest
+	if err := json.Unmarshal(jobs[0].Request, &req); err != nil || req.Prompt != "A lighthouse." {
+		t.Errorf("Expected the request to be journaled, got %s (%v)", jobs[0].Request, err)
+	}
+
+	outDir := t.TempDir()
+	if err := newClient(journal).ResumeJobs(ctx, journal, outDir, 1); err != nil {
+		t.Fatalf("ResumeJobs failed: %v", err)
+	}
+
+	jobs, err = Jobs(ctx, journal)
+	if err != nil {
+		t.Fatalf("Jobs failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Status != GenerationStatusComplete || !jobs[0].Downloaded || len(jobs[0].Files) != 1 {
+		t.Fatalf("Unexpected jobs: %+v", jobs)
+	}
+	if data, err := os.ReadFile(jobs[0].Files[0]); err != nil || string(data) != "png data" {
+		t.Errorf("Expected the image in %s, got %q (%v)", jobs[0].Files[0], data, err)
+	}
+	if filepath.Ext(jobs[0].Files[0]) != ".png" {
+		t.Errorf("Expected a .png file, got %s", jobs[0].Files[0])
+	}
+
+	entries, _ := journal.Entries(ctx)
+	events := []JournalEvent{}
+	for _, e := range entries {
+		events = append(events, e.Event)
+	}
+	if len(events) != 3 || events[1] != JournalCompleted || events[2] != JournalDownloaded {
+		t.Errorf("Unexpected journal events: %v", events)
+	}
+
+	jobs, _ = IncompleteJobs(ctx, journal)
+	if len(jobs) != 0 {
+		t.Errorf("Expected no incomplete jobs, got %+v", jobs)
+	}
+}
+
+// TestJournalResumeCancelled tests that a job still running when ResumeJobs is cancelled stays incomplete.
+func TestJournalResumeCancelled(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generations/gen-1" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{ID: Ptr("gen-1"), Status: Ptr(GenerationStatusPending)}})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "jobs.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	defer journal.Close()
+	ctx := context.Background()
+	journal.Append(ctx, JournalEntry{Event: JournalSubmitted, JobID: "gen-1", Kind: JobGeneration, Operation: "Images.CreateImageGeneration"})
+
+	waitCtx, cancel := context.WithTimeout(ctx, 150*time.Millisecond)
+	defer cancel()
+	if err := client.ResumeJobs(waitCtx, journal, t.TempDir(), 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	jobs, err := IncompleteJobs(ctx, journal)
+	if err != nil {
+		t.Fatalf("IncompleteJobs failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Status != GenerationStatusPending {
+		t.Errorf("Expected the job to stay pending, got %+v", jobs)
+	}
+}
+
+// TestJournalResumeMotion tests that resuming a motion job downloads its video,
+// and a texture job its images.
+func TestJournalResumeMotion(t *testing.T) {
+	// Mock server setup
+	var server *httptest.Server
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/generations/gen-1":
+			json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{
+				ID:     Ptr("gen-1"),
+				Status: Ptr(GenerationStatusComplete),
+				GeneratedImages: []GeneratedImage{{
+					ID:           Ptr("img-1"),
+					URL:          Ptr(server.URL + "/img-1.jpg"),
+					MotionMP4URL: Ptr(server.URL + "/img-1"),
+				}},
+			}})
+		case "/img-1":
+			w.Write([]byte("mp4 data"))
+		case "/generations/tex-1":
+			json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{
+				ID:              Ptr("tex-1"),
+				Status:          Ptr(GenerationStatusComplete),
+				GeneratedImages: []GeneratedImage{{ID: Ptr("img-2"), URL: Ptr(server.URL + "/img-2.png")}},
+			}})
+		case "/img-2.png":
+			w.Write([]byte("png data"))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server = httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "jobs.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	defer journal.Close()
+	ctx := context.Background()
+	journal.Append(ctx, JournalEntry{Event: JournalSubmitted, JobID: "gen-1", Kind: JobGeneration, Operation: "Motion.CreateSVDMotionGeneration"})
+	journal.Append(ctx, JournalEntry{Event: JournalSubmitted, JobID: "tex-1", Kind: JobTexture, Operation: "Texture.CreateTextureGeneration"})
+
+	if err := client.ResumeJobs(ctx, journal, t.TempDir(), time.Millisecond); err != nil {
+		t.Fatalf("ResumeJobs failed: %v", err)
+	}
+	jobs, _ := Jobs(ctx, journal)
+	if len(jobs) != 2 || !jobs[0].Downloaded || len(jobs[0].Files) != 1 || filepath.Ext(jobs[0].Files[0]) != ".mp4" {
+		t.Fatalf("Expected the video to be downloaded, got %+v", jobs[0])
+	}
+	if data, _ := os.ReadFile(jobs[0].Files[0]); string(data) != "mp4 data" {
+		t.Errorf("Expected the video, got %q", data)
+	}
+	if !jobs[1].Downloaded || len(jobs[1].Files) != 1 || filepath.Ext(jobs[1].Files[0]) != ".png" {
+		t.Errorf("Expected the texture to be downloaded, got %+v", jobs[1])
+	}
+}
+
+// brokenJournal is a Journal whose entries can't be read.
+type brokenJournal struct{}
+
+func (brokenJournal) Append(ctx context.Context, entry JournalEntry) error { return nil }
+
+func (brokenJournal) Entries(ctx context.Context) ([]JournalEntry, error) {
+	return nil, errors.New("journal unreadable")
+}
+
+// TestJournalMiddlewareSkips tests that dry run calls aren't journaled and that
+// a journal that can't be read fails the call naming the job.
+func TestJournalMiddlewareSkips(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /generations":
+			json.NewEncoder(w).Encode(CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr("gen-1")}})
+		case "POST /pricing-calculator":
+			json.NewEncoder(w).Encode(CalculateAPICostResponse{CalculateProductionApiServiceCost: ServiceCost{Cost: Ptr(5)}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	newClient := func(j Journal) *Client {
+		client := &Client{
+			BaseURL:    server.URL,
+			HTTPClient: server.Client(),
+			APIKey:     "test-api-key",
+		}
+		client.Images = client.NewImagesService()
+		client.Use(JournalMiddleware(j))
+		return client
+	}
+
+	ctx := context.Background()
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "jobs.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	defer journal.Close()
+	client := newClient(journal)
+	client.DryRun = &DryRun{Logger: log.New(io.Discard, "", 0)}
+	if _, err := client.Images.CreateImageGeneration(ctx, CreateGenerationRequest{Prompt: "A lighthouse."}); err != nil {
+		t.Fatalf("CreateImageGeneration failed: %v", err)
+	}
+	if entries, _ := journal.Entries(ctx); len(entries) != 0 {
+		t.Errorf("Expected no entries for a dry run, got %+v", entries)
+	}
+
+	_, err = newClient(brokenJournal{}).Images.CreateImageGeneration(ctx, CreateGenerationRequest{Prompt: "A lighthouse."})
+	if err == nil || !strings.Contains(err.Error(), "journaling job gen-1 failed") {
+		t.Errorf("Expected an error naming the job, got %v", err)
+	}
+}
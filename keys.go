@@ -0,0 +1,439 @@
+package leonardo
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyProvider supplies the API key sent with each request. When set on
+// Client.KeyProvider it takes the place of Client.APIKey.
+type KeyProvider interface {
+	// APIKey returns the key for the request being built with ctx. The call in
+	// progress, if any, is available through CallFromContext.
+	APIKey(ctx context.Context) (string, error)
+}
+
+// ErrNoAPIKey is returned when a KeyPool has no usable key left.
+var ErrNoAPIKey = errors.New("no usable API key")
+
+// KeySelection decides which key a KeyPool uses for a new call.
+type KeySelection int
+
+const (
+	// KeyRoundRobin rotates through the usable keys.
+	KeyRoundRobin KeySelection = iota
+	// KeyMostTokens picks the usable key with the most API tokens remaining,
+	// as reported by GetUserInfo.
+	KeyMostTokens
+)
+
+// KeyPool spreads calls over several API keys. Install it with
+//
+//	pool := client.NewKeyPool(keys...)
+//	client.KeyProvider = pool
+//	client.Use(pool.Middleware())
+//
+// The middleware retries a call with another key when the API rejects a key
+// (401), reports it out of credits (402) or rate limits it (429), tracks the
+// credits each key spends, and sends follow-up calls about a job, such as
+// fetching or deleting a generation or creating a variation of one of its
+// images, with the key that created it.
+type KeyPool struct {
+	client *Client
+
+	// Selection decides which key a new call uses.
+	Selection KeySelection
+
+	// Cooldown is how long a rate limited key is skipped. Zero uses 30 seconds.
+	Cooldown time.Duration
+
+	// TokenTTL is how long token balances are trusted before KeyMostTokens
+	// fetches them again, and how long a key out of credits is skipped. Zero
+	// uses five minutes.
+	TokenTTL time.Duration
+
+	// MaxPins is how many job and image IDs are remembered for follow-up
+	// calls. Zero uses DefaultMaxPins.
+	MaxPins int
+
+	mu         sync.Mutex
+	keys       []*pooledKey
+	next       int
+	pins       map[string]*list.Element
+	pinsOrder  *list.List
+	refreshing chan struct{} // closed when the token refresh in progress ends
+}
+
+// DefaultMaxPins is how many IDs a KeyPool remembers when MaxPins is unset.
+const DefaultMaxPins = 10000
+
+// keyPin is a job or image ID remembered by a KeyPool.
+type keyPin struct {
+	id     string
+	key    *pooledKey
+	images []string // IDs of a generation's images, forgotten with it
+}
+
+// pooledKey is a key in a KeyPool and its usage.
+type pooledKey struct {
+	key       string
+	quota     int
+	spent     int
+	requests  int
+	failures  int
+	tokens    *int
+	tokensAt  time.Time
+	skipUntil time.Time
+	disabled  bool
+}
+
+// KeyStats reports the usage of a key in a KeyPool.
+type KeyStats struct {
+	// Key is the last four characters of the key.
+	Key      string
+	Requests int
+	Failures int
+	// Spent is the API credits spent with the key, and Quota the most it may
+	// spend, or zero for no limit.
+	Spent int
+	Quota int
+	// Tokens is the last known token balance, or nil if not yet fetched.
+	Tokens *int
+	// Disabled is set once the API rejects the key.
+	Disabled bool
+	// SkipUntil is when a rate limited or exhausted key becomes usable again.
+	SkipUntil time.Time
+}
+
+// NewKeyPool creates a KeyPool of the given keys.
+func (c *Client) NewKeyPool(keys ...string) *KeyPool {
+	p := &KeyPool{client: c, pins: map[string]*list.Element{}, pinsOrder: list.New()}
+	for _, key := range keys {
+		p.keys = append(p.keys, &pooledKey{key: key})
+	}
+	return p
+}
+
+// SetQuota limits the API credits key may spend through the pool. Once it is
+// reached the key is only used for follow-up calls about its own jobs.
+func (p *KeyPool) SetQuota(key string, credits int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k := p.find(key); k != nil {
+		k.quota = credits
+	}
+}
+
+// Pin sends follow-up calls about the job or image with the given ID with key,
+// such as after restoring jobs created before a restart. The pool remembers at
+// most MaxPins IDs, its own and pinned ones alike, forgetting the least
+// recently used first, and forgets a job, with its images, once it is deleted
+// through the pool.
+func (p *KeyPool) Pin(id, key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k := p.find(key); k != nil {
+		p.pin(id, k)
+	}
+}
+
+// pin remembers that id belongs to k. The caller must hold p.mu.
+func (p *KeyPool) pin(id string, k *pooledKey) {
+	if el, ok := p.pins[id]; ok {
+		el.Value.(*keyPin).key = k
+		p.pinsOrder.MoveToFront(el)
+		return
+	}
+	p.pins[id] = p.pinsOrder.PushFront(&keyPin{id: id, key: k})
+
+	limit := p.MaxPins
+	if limit <= 0 {
+		limit = DefaultMaxPins
+	}
+	for p.pinsOrder.Len() > limit {
+		oldest := p.pinsOrder.Back()
+		p.pinsOrder.Remove(oldest)
+		delete(p.pins, oldest.Value.(*keyPin).id)
+	}
+}
+
+// find returns the pooled key, or nil. The caller must hold p.mu.
+func (p *KeyPool) find(key string) *pooledKey {
+	for _, k := range p.keys {
+		if k.key == key {
+			return k
+		}
+	}
+	return nil
+}
+
+// Stats returns the usage of every key, in the order they were added.
+func (p *KeyPool) Stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		s := KeyStats{
+			Key:       k.key[max(0, len(k.key)-4):],
+			Requests:  k.requests,
+			Failures:  k.failures,
+			Spent:     k.spent,
+			Quota:     k.quota,
+			Disabled:  k.disabled,
+			SkipUntil: k.skipUntil,
+		}
+		if k.tokens != nil {
+			s.Tokens = Ptr(*k.tokens)
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+type poolKeyContextKey struct{}
+
+// APIKey implements KeyProvider.
+func (p *KeyPool) APIKey(ctx context.Context) (string, error) {
+	if k, ok := ctx.Value(poolKeyContextKey{}).(*pooledKey); ok {
+		return k.key, nil
+	}
+	if call, ok := CallFromContext(ctx); ok {
+		if k := p.pinned(call); k != nil {
+			return k.key, nil
+		}
+	}
+	k, err := p.pick(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	return k.key, nil
+}
+
+// Middleware returns the middleware that chooses a key for each call, fails
+// over to another key when one is refused, and records usage.
+func (p *KeyPool) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			k, ok := ctx.Value(poolKeyContextKey{}).(*pooledKey)
+			if !ok {
+				k = p.pinned(call)
+			}
+			if k != nil {
+				err := next(context.WithValue(ctx, poolKeyContextKey{}, k), call)
+				p.observe(k, call, err)
+				return err
+			}
+
+			tried := map[*pooledKey]bool{}
+			var lastErr error
+			for {
+				k, err := p.pick(ctx, tried)
+				if err != nil {
+					if lastErr != nil {
+						return lastErr
+					}
+					return err
+				}
+				err = next(context.WithValue(ctx, poolKeyContextKey{}, k), call)
+				p.observe(k, call, err)
+				if err == nil || keyErrorStatus(err) == 0 {
+					return err
+				}
+				tried[k] = true
+				lastErr = err
+			}
+		}
+	}
+}
+
+// keyErrorStatus returns the status code of an error that another key may not
+// run into, or zero.
+func keyErrorStatus(err error) int {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return 0
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusTooManyRequests:
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// pinned returns the key that created the job or image a call is about, or nil.
+func (p *KeyPool) pinned(call *Call) *pooledKey {
+	if call.ID == "" {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	el, ok := p.pins[call.ID]
+	if !ok {
+		return nil
+	}
+	p.pinsOrder.MoveToFront(el)
+	return el.Value.(*keyPin).key
+}
+
+// usable reports whether k may be chosen for a new call. The caller must hold p.mu.
+func (k *pooledKey) usable(now time.Time) bool {
+	return !k.disabled && !now.Before(k.skipUntil) && (k.quota == 0 || k.spent < k.quota)
+}
+
+// pick chooses a usable key that hasn't been tried.
+func (p *KeyPool) pick(ctx context.Context, tried map[*pooledKey]bool) (*pooledKey, error) {
+	if p.Selection == KeyMostTokens {
+		p.refreshTokens(ctx)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	var best *pooledKey
+	for i := range p.keys {
+		idx := (p.next + i) % len(p.keys)
+		k := p.keys[idx]
+		if tried[k] || !k.usable(now) {
+			continue
+		}
+		if p.Selection == KeyRoundRobin {
+			p.next = idx + 1
+			return k, nil
+		}
+		if best == nil || deref(k.tokens) > deref(best.tokens) {
+			best = k
+		}
+	}
+	if best == nil {
+		return nil, ErrNoAPIKey
+	}
+	return best, nil
+}
+
+// refreshTokens fetches the token balances of usable keys that are unknown or
+// older than TokenTTL. Only one refresh runs at a time; concurrent callers wait
+// for it. A failed fetch is recorded by the pool's middleware.
+func (p *KeyPool) refreshTokens(ctx context.Context) {
+	ttl := p.TokenTTL
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	p.mu.Lock()
+	if done := p.refreshing; done != nil {
+		p.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		return
+	}
+	now := time.Now()
+	var stale []*pooledKey
+	for _, k := range p.keys {
+		if k.usable(now) && (k.tokens == nil || now.Sub(k.tokensAt) > ttl) {
+			stale = append(stale, k)
+		}
+	}
+	if len(stale) == 0 {
+		p.mu.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	p.refreshing = done
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.refreshing = nil
+		p.mu.Unlock()
+		close(done)
+	}()
+
+	for _, k := range stale {
+		resp, err := p.client.User.GetUserInfo(context.WithValue(ctx, poolKeyContextKey{}, k))
+		if err != nil {
+			continue
+		}
+		if details := resp.Details(); details != nil {
+			tokens := deref(details.APISubscriptionTokens) + deref(details.APIPaidTokens)
+			p.mu.Lock()
+			k.tokens, k.tokensAt = &tokens, time.Now()
+			p.mu.Unlock()
+		}
+	}
+}
+
+// observe records the outcome of a call made with k.
+func (p *KeyPool) observe(k *pooledKey, call *Call, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k.requests++
+	if err != nil {
+		k.failures++
+		switch keyErrorStatus(err) {
+		case http.StatusUnauthorized:
+			k.disabled = true
+		case http.StatusPaymentRequired:
+			ttl := p.TokenTTL
+			if ttl <= 0 {
+				ttl = 5 * time.Minute
+			}
+			k.skipUntil, k.tokens = time.Now().Add(ttl), Ptr(0)
+		case http.StatusTooManyRequests:
+			cooldown := p.Cooldown
+			if cooldown <= 0 {
+				cooldown = 30 * time.Second
+			}
+			k.skipUntil = time.Now().Add(cooldown)
+		}
+		return
+	}
+
+	if cost := responseCreditCost(call.Response); cost > 0 {
+		k.spent += cost
+		if k.tokens != nil {
+			*k.tokens -= cost
+		}
+	}
+	if strings.HasPrefix(call.Operation, "Delete") && call.ID != "" {
+		p.unpin(call.ID)
+		return
+	}
+	if id := responseJobID(call.Response); id != "" {
+		p.pin(id, k)
+	}
+	if resp, ok := call.Response.(*GetGenerationResponse); ok {
+		var images []string
+		for _, img := range resp.Images() {
+			if img.ID != nil {
+				p.pin(*img.ID, k)
+				images = append(images, *img.ID)
+			}
+		}
+		if el, ok := p.pins[deref(resp.GenerationsByPK.ID)]; ok {
+			el.Value.(*keyPin).images = images
+		}
+	}
+}
+
+// unpin forgets id and the images pinned with it. The caller must hold p.mu.
+func (p *KeyPool) unpin(id string) {
+	el, ok := p.pins[id]
+	if !ok {
+		return
+	}
+	p.pinsOrder.Remove(el)
+	delete(p.pins, id)
+	for _, img := range el.Value.(*keyPin).images {
+		if el, ok := p.pins[img]; ok {
+			p.pinsOrder.Remove(el)
+			delete(p.pins, img)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestKeyPool tests failover, spend tracking and pinning follow-up calls.
+func TestKeyPool(t *testing.T) {
+	var mu sync.Mutex
+	var used []string
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		used = append(used, key)
+
+		switch {
+		case key == "key-revoked":
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(APIErrorResponse{Message: "Invalid API key."})
+		case key == "key-busy" && r.Method == "POST":
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(APIErrorResponse{Message: "Too many requests."})
+		case r.URL.Path == "/generations":
+			json.NewEncoder(w).Encode(CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr("gen-" + key), APICreditCost: Ptr(8)}})
+		case strings.HasPrefix(r.URL.Path, "/generations/"):
+			id := strings.TrimPrefix(r.URL.Path, "/generations/")
+			json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{
+				ID:              Ptr(id),
+				Status:          Ptr(GenerationStatusComplete),
+				GeneratedImages: []GeneratedImage{{ID: Ptr("img-" + id)}},
+			}})
+		case r.URL.Path == "/variations/upscale":
+			json.NewEncoder(w).Encode(UpscaleVariationResponse{SdUpscaleJob: VariationJob{ID: Ptr("var-1"), APICreditCost: Ptr(2)}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+	}
+	client.Images = client.NewImagesService()
+	client.Variation = client.NewVariationService()
+
+	pool := client.NewKeyPool("key-revoked", "key-busy", "key-ok")
+	client.KeyProvider = pool
+	client.Use(pool.Middleware())
+
+	ctx := context.Background()
+	created, err := client.Images.CreateImageGeneration(ctx, CreateGenerationRequest{Prompt: "A lighthouse."})
+	if err != nil {
+		t.Fatalf("CreateImageGeneration failed: %v", err)
+	}
+	if id := created.GenerationID(); id != "gen-key-ok" {
+		t.Fatalf("Expected the call to fail over to key-ok, got %s", id)
+	}
+
+	// Follow-up calls stay on the creating key.
+	if _, err := client.Images.GetImageGeneration(ctx, "gen-key-ok"); err != nil {
+		t.Fatalf("GetImageGeneration failed: %v", err)
+	}
+	if _, err := client.Variation.CreateUpscaleVariation(ctx, "img-gen-key-ok"); err != nil {
+		t.Fatalf("CreateUpscaleVariation failed: %v", err)
+	}
+	if want := "key-revoked key-busy key-ok key-ok key-ok"; strings.Join(used, " ") != want {
+		t.Errorf("Expected keys %q, got %q", want, strings.Join(used, " "))
+	}
+
+	stats := pool.Stats()
+	if !stats[0].Disabled || stats[1].SkipUntil.IsZero() || stats[2].Spent != 10 || stats[2].Requests != 3 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats[2].Key != "y-ok" {
+		t.Errorf("Expected the key to be redacted, got %s", stats[2].Key)
+	}
+
+	// With its quota spent and the other keys unusable, no key is left.
+	pool.SetQuota("key-ok", 10)
+	if _, err := client.Images.CreateImageGeneration(ctx, CreateGenerationRequest{Prompt: "A lighthouse."}); !errors.Is(err, ErrNoAPIKey) {
+		t.Errorf("Expected ErrNoAPIKey, got %v", err)
+	}
+}
+
+// TestKeyPoolMostTokens tests picking the key with the most tokens remaining.
+func TestKeyPoolMostTokens(t *testing.T) {
+	tokens := map[string]int{"key-a": 100, "key-b": 500, "key-c": 300}
+	var mu sync.Mutex
+	var generations []string
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		switch r.URL.Path {
+		case "/me":
+			json.NewEncoder(w).Encode(GetUserInfoResponse{UserDetails: []UserDetails{{APISubscriptionTokens: Ptr(tokens[key])}}})
+		case "/generations":
+			generations = append(generations, key)
+			json.NewEncoder(w).Encode(CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr("gen-1"), APICreditCost: Ptr(250)}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+	}
+	client.Images = client.NewImagesService()
+	client.User = client.NewUserService()
+
+	pool := client.NewKeyPool("key-a", "key-b", "key-c")
+	pool.Selection = KeyMostTokens
+	client.KeyProvider = pool
+	client.Use(pool.Middleware())
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Images.CreateImageGeneration(context.Background(), CreateGenerationRequest{Prompt: "A lighthouse."}); err != nil {
+			t.Fatalf("CreateImageGeneration failed: %v", err)
+		}
+	}
+	// key-b drops to 250 tokens after the first generation, below key-c.
+	if strings.Join(generations, " ") != "key-b key-c" {
+		t.Errorf("Expected key-b then key-c, got %v", generations)
+	}
+	if tokens := pool.Stats()[1].Tokens; tokens == nil || *tokens != 250 {
+		t.Errorf("Expected key-b to have 250 tokens left, got %v", tokens)
+	}
+}
+
+// TestKeyPoolPinEviction tests that pins are bounded and dropped when their job is deleted.
+func TestKeyPoolPinEviction(t *testing.T) {
+	client := &Client{}
+	pool := client.NewKeyPool("key-a", "key-b")
+	pool.MaxPins = 2
+
+	pool.Pin("gen-1", "key-a")
+	pool.Pin("gen-2", "key-b")
+	pool.pinned(&Call{ID: "gen-1"}) // gen-1 is now the most recently used
+	pool.Pin("gen-3", "key-b")
+	if pool.pinned(&Call{ID: "gen-2"}) != nil {
+		t.Error("Expected the least recently used pin to be evicted")
+	}
+	if k := pool.pinned(&Call{ID: "gen-1"}); k == nil || k.key != "key-a" {
+		t.Errorf("Expected gen-1 to stay pinned to key-a, got %v", k)
+	}
+
+	pool.observe(pool.keys[0], &Call{Service: "Images", Operation: "DeleteGeneration", ID: "gen-1", Response: &DeleteGenerationResponse{}}, nil)
+	if pool.pinned(&Call{ID: "gen-1"}) != nil || len(pool.pins) != 1 {
+		t.Errorf("Expected the deleted job to be unpinned, got %d pins", len(pool.pins))
+	}
+
+	// Deleting a generation also forgets its images.
+	pool.MaxPins = 10
+	pool.observe(pool.keys[0], &Call{Service: "Images", Operation: "GetImageGeneration", ID: "gen-4", Response: &GetGenerationResponse{
+		GenerationsByPK: GenerationDetails{ID: Ptr("gen-4"), GeneratedImages: []GeneratedImage{{ID: Ptr("img-1")}, {ID: Ptr("img-2")}}},
+	}}, nil)
+	if pool.pinned(&Call{ID: "img-2"}) == nil {
+		t.Fatal("Expected the generation's images to be pinned")
+	}
+	pool.observe(pool.keys[0], &Call{Service: "Images", Operation: "DeleteGeneration", ID: "gen-4", Response: &DeleteGenerationResponse{}}, nil)
+	if pool.pinned(&Call{ID: "img-1"}) != nil || pool.pinned(&Call{ID: "img-2"}) != nil || len(pool.pins) != 1 {
+		t.Errorf("Expected the deleted generation's images to be unpinned, got %d pins", len(pool.pins))
+	}
+}
+
+// TestKeyPoolRefresh tests that concurrent calls share a token refresh and that
+// a failed refresh is counted once.
+func TestKeyPoolRefresh(t *testing.T) {
+	var mu sync.Mutex
+	refreshes := map[string]int{}
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		switch r.URL.Path {
+		case "/me":
+			mu.Lock()
+			refreshes[key]++
+			mu.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			if key == "key-b" {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(APIErrorResponse{Message: "Internal error."})
+				return
+			}
+			json.NewEncoder(w).Encode(GetUserInfoResponse{UserDetails: []UserDetails{{APISubscriptionTokens: Ptr(1000)}}})
+		case "/generations":
+			json.NewEncoder(w).Encode(CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr("gen-1"), APICreditCost: Ptr(8)}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+	}
+	client.Images = client.NewImagesService()
+	client.User = client.NewUserService()
+
+	pool := client.NewKeyPool("key-a", "key-b")
+	pool.Selection = KeyMostTokens
+	client.KeyProvider = pool
+	client.Use(pool.Middleware())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Images.CreateImageGeneration(context.Background(), CreateGenerationRequest{Prompt: "A lighthouse."}); err != nil {
+				t.Errorf("CreateImageGeneration failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if refreshes["key-a"] != 1 {
+		t.Errorf("Expected key-a to be refreshed once, got %d", refreshes["key-a"])
+	}
+	if stats := pool.Stats(); stats[1].Failures != refreshes["key-b"] {
+		t.Errorf("Expected %d failures for key-b, got %d", refreshes["key-b"], stats[1].Failures)
+	}
+}
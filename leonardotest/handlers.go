@@ -0,0 +1,1020 @@
+package leonardotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emmaly/leonardo"
+)
+
+// API credit costs charged by the fake server.
+const (
+	CostPerImage          = 2
+	CostVariation         = 5
+	CostUniversalUpscaler = 10
+	CostModelTraining     = 500
+	CostTextureGeneration = 10
+	CostMotionGeneration  = 25
+	CostPrompt            = 4
+)
+
+type generation struct {
+	id        string
+	job       job
+	req       leonardo.CreateGenerationRequest
+	imageIDs  []string
+	cost      int
+	isTexture bool
+}
+
+type generatedImage struct {
+	id           string
+	generationID string
+	variationIDs []string
+}
+
+type variation struct {
+	id        string
+	imageID   string
+	job       job
+	transform leonardo.TransformType
+}
+
+type initImage struct {
+	id        string
+	key       string
+	createdAt time.Time
+}
+
+type dataset struct {
+	id          string
+	name        string
+	description *string
+	createdAt   time.Time
+	updatedAt   time.Time
+	images      []datasetImage
+}
+
+type datasetImage struct {
+	id        string
+	url       string
+	createdAt time.Time
+}
+
+type customModel struct {
+	id        string
+	job       job
+	req       leonardo.TrainCustomModelRequest
+	updatedAt time.Time
+}
+
+type modelAsset struct {
+	id        string
+	name      *string
+	key       string
+	createdAt time.Time
+}
+
+// PlatformModels are the models listed by GET /platformModels.
+var PlatformModels = []map[string]interface{}{
+	{"id": "b24e16ff-06e3-43eb-8d33-4416c2d75876", "name": "Leonardo Lightning XL", "description": "A versatile and fast model.", "baseModel": "SDXL_LIGHTNING"},
+	{"id": "6b645e3a-d64f-4341-a6d8-7a3690fbf042", "name": "Leonardo Phoenix", "description": "Prompt adherence and text rendering.", "baseModel": "PHOENIX"},
+	{"id": "aa77f04e-3eec-4034-9c07-d0f619684628", "name": "Leonardo Kino XL", "description": "Cinematic outputs.", "baseModel": "SDXL_1_0"},
+}
+
+// Elements are the LoRAs listed by GET /elements.
+var Elements = []leonardo.Lora{
+	{AKUUID: leonardo.Ptr("element-1"), BaseModel: leonardo.Ptr("SDXL_1_0"), Name: leonardo.Ptr("Crystalline"), WeightDefault: leonardo.Ptr(1), WeightMin: leonardo.Ptr(-1), WeightMax: leonardo.Ptr(2)},
+	{AKUUID: leonardo.Ptr("element-2"), BaseModel: leonardo.Ptr("SDXL_1_0"), Name: leonardo.Ptr("Glass & Steel"), WeightDefault: leonardo.Ptr(1), WeightMin: leonardo.Ptr(-1), WeightMax: leonardo.Ptr(2)},
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /generations", s.createGeneration)
+	mux.HandleFunc("GET /generations/{id}", s.getGeneration)
+	mux.HandleFunc("DELETE /generations/{id}", s.deleteGeneration)
+	mux.HandleFunc("GET /generations/user/{userId}", s.getGenerationsByUser)
+	mux.HandleFunc("POST /generations-texture", s.createTextureGeneration)
+	mux.HandleFunc("POST /generations-motion-svd", s.createMotionGeneration)
+
+	mux.HandleFunc("POST /variations/unzoom", s.createVariation(leonardo.TransformTypeUnzoom, "sdUnzoomJob"))
+	mux.HandleFunc("POST /variations/upscale", s.createVariation(leonardo.TransformTypeUpscale, "sdUpscaleJob"))
+	mux.HandleFunc("POST /variations/nobg", s.createVariation(leonardo.TransformTypeNoBackground, "sdNobgJob"))
+	mux.HandleFunc("POST /variations/universal-upscaler", s.universalUpscaler)
+	mux.HandleFunc("GET /variations/{id}", s.getVariation)
+
+	mux.HandleFunc("POST /init-image", s.uploadInitImage)
+	mux.HandleFunc("GET /init-image/{id}", s.getInitImage)
+	mux.HandleFunc("DELETE /init-image/{id}", s.deleteInitImage)
+	mux.HandleFunc("POST /canvas-init-image", s.uploadCanvasInitImage)
+
+	mux.HandleFunc("POST /datasets", s.createDataset)
+	mux.HandleFunc("GET /datasets/{id}", s.getDataset)
+	mux.HandleFunc("DELETE /datasets/{id}", s.deleteDataset)
+	mux.HandleFunc("POST /datasets/{id}/upload", s.uploadDatasetImage)
+	mux.HandleFunc("POST /datasets/{id}/upload/gen", s.uploadGeneratedImageToDataset)
+
+	mux.HandleFunc("POST /models", s.trainCustomModel)
+	mux.HandleFunc("GET /models/{id}", s.getCustomModel)
+	mux.HandleFunc("PUT /models/{id}", s.updateCustomModel)
+	mux.HandleFunc("DELETE /models/{id}", s.deleteCustomModel)
+	mux.HandleFunc("GET /platformModels", s.listPlatformModels)
+
+	mux.HandleFunc("POST /models-3d/upload", s.upload3DModel)
+	mux.HandleFunc("GET /models-3d/user/{userId}", s.get3DModelsByUser)
+	mux.HandleFunc("GET /models-3d/{id}", s.get3DModel)
+	mux.HandleFunc("DELETE /models-3d/{id}", s.delete3DModel)
+
+	mux.HandleFunc("GET /elements", s.listElements)
+	mux.HandleFunc("POST /prompt/random", s.randomPrompt)
+	mux.HandleFunc("POST /prompt/improve", s.improvePrompt)
+	mux.HandleFunc("GET /me", s.me)
+	mux.HandleFunc("POST /pricing-calculator", s.calculateCost)
+
+	mux.HandleFunc("POST /s3/upload", s.s3Upload)
+	mux.HandleFunc("GET /s3/{key...}", s.s3Object)
+	mux.HandleFunc("GET /files/{name}", s.file)
+
+	return s.serve(mux)
+}
+
+// decode reads the JSON request body into v, answering 400 on failure.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		writeError(w, r, http.StatusBadRequest, "bad-request", fmt.Sprintf("Invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func notFound(w http.ResponseWriter, r *http.Request, what string) {
+	writeError(w, r, http.StatusNotFound, "not-found", what+" not found.")
+}
+
+func insufficientCredits(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusPaymentRequired, "insufficient-credits", "Not enough API credits.")
+}
+
+func generationCost(req leonardo.CreateGenerationRequest) int {
+	n := 4
+	if req.NumImages != nil {
+		n = *req.NumImages
+	}
+	return n * CostPerImage
+}
+
+// Generations
+
+func (s *Server) createGeneration(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.CreateGenerationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Prompt == "" {
+		writeError(w, r, http.StatusBadRequest, "bad-request", "Prompt is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cost := generationCost(req)
+	if !s.charge(cost) {
+		insufficientCredits(w, r)
+		return
+	}
+	g := s.addGeneration(req, cost, false)
+
+	writeJSON(w, map[string]interface{}{
+		"sdGenerationJob": map[string]interface{}{"generationId": g.id, "apiCreditCost": cost},
+	})
+}
+
+// addGeneration stores a new generation with its images. The caller must hold s.mu.
+func (s *Server) addGeneration(req leonardo.CreateGenerationRequest, cost int, isTexture bool) *generation {
+	if req.Seed == nil {
+		req.Seed = leonardo.Ptr(s.seq * 7919)
+	}
+	g := &generation{id: s.newID(), job: s.newJob(), req: req, cost: cost, isTexture: isTexture}
+	n := 4
+	if req.NumImages != nil {
+		n = *req.NumImages
+	}
+	if isTexture {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		img := &generatedImage{id: s.newID(), generationID: g.id}
+		s.images[img.id] = img
+		g.imageIDs = append(g.imageIDs, img.id)
+	}
+	s.generations[g.id] = g
+	return g
+}
+
+func (s *Server) getGeneration(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.generations[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Generation")
+		return
+	}
+	writeJSON(w, map[string]interface{}{"generations_by_pk": s.generationJSON(g)})
+}
+
+// generationJSON renders a generation the way GET /generations/{id} does. The caller must hold s.mu.
+func (s *Server) generationJSON(g *generation) map[string]interface{} {
+	status := s.status(g.job)
+	width, height := 1024, 768
+	if g.req.Width != nil {
+		width = *g.req.Width
+	}
+	if g.req.Height != nil {
+		height = *g.req.Height
+	}
+	steps := 15
+	if g.req.NumInferenceSteps != nil {
+		steps = *g.req.NumInferenceSteps
+	}
+	modelID := "b24e16ff-06e3-43eb-8d33-4416c2d75876"
+	if g.req.ModelID != nil {
+		modelID = *g.req.ModelID
+	}
+
+	elements := []map[string]interface{}{}
+	for i, e := range g.req.Elements {
+		lora := map[string]interface{}{"akUUID": e.AKUUID}
+		for _, known := range Elements {
+			if known.AKUUID != nil && *known.AKUUID == e.AKUUID {
+				lora["name"] = known.Name
+				lora["baseModel"] = known.BaseModel
+			}
+		}
+		elements = append(elements, map[string]interface{}{
+			"id":            fmt.Sprintf("%s-element-%d", g.id, i),
+			"lora":          lora,
+			"weightApplied": e.Weight,
+		})
+	}
+
+	images := []map[string]interface{}{}
+	if status == leonardo.GenerationStatusComplete {
+		for _, id := range g.imageIDs {
+			img := s.images[id]
+			variations := []map[string]interface{}{}
+			for _, vid := range img.variationIDs {
+				variations = append(variations, s.variationJSON(s.variations[vid]))
+			}
+			images = append(images, map[string]interface{}{
+				"id":                                 id,
+				"url":                                s.URL + "/files/" + id + ".png",
+				"nsfw":                               false,
+				"likeCount":                          0,
+				"motionMP4Url":                       nil,
+				"generated_image_variation_generics": variations,
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"id":                  g.id,
+		"status":              status,
+		"createdAt":           formatTime(g.job.createdAt),
+		"guidanceScale":       g.req.GuidanceScale,
+		"imageHeight":         height,
+		"imageWidth":          width,
+		"inferenceSteps":      steps,
+		"modelId":             modelID,
+		"negativePrompt":      g.req.NegativePrompt,
+		"photoReal":           g.req.PhotoReal,
+		"photoRealStrength":   g.req.PhotoRealStrength,
+		"presetStyle":         g.req.PresetStyle,
+		"prompt":              g.req.Prompt,
+		"promptMagic":         g.req.PromptMagic,
+		"promptMagicStrength": g.req.PromptMagicStrength,
+		"promptMagicVersion":  g.req.PromptMagicVersion,
+		"public":              g.req.Public,
+		"scheduler":           g.req.Scheduler,
+		"sdVersion":           g.req.SDVersion,
+		"seed":                g.req.Seed,
+		"ultra":               g.req.Ultra,
+		"generation_elements": elements,
+		"generated_images":    images,
+	}
+}
+
+func (s *Server) deleteGeneration(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	g, ok := s.generations[id]
+	if !ok {
+		notFound(w, r, "Generation")
+		return
+	}
+	for _, imgID := range g.imageIDs {
+		delete(s.images, imgID)
+	}
+	delete(s.generations, id)
+	writeJSON(w, map[string]interface{}{"delete_generations_by_pk": map[string]interface{}{"id": id}})
+}
+
+func (s *Server) getGenerationsByUser(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("userId") != UserID {
+		writeJSON(w, map[string]interface{}{"generations": []interface{}{}})
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := make([]*generation, 0, len(s.generations))
+	for _, g := range s.generations {
+		all = append(all, g)
+	}
+	// Newest first, as the API returns them.
+	slices.SortFunc(all, func(a, b *generation) int { return strings.Compare(b.id, a.id) })
+	if offset > len(all) {
+		offset = len(all)
+	}
+	all = all[offset:]
+	if limit > 0 && limit < len(all) {
+		all = all[:limit]
+	}
+
+	gens := []map[string]interface{}{}
+	for _, g := range all {
+		gens = append(gens, s.generationJSON(g))
+	}
+	writeJSON(w, map[string]interface{}{"generations": gens})
+}
+
+func (s *Server) createTextureGeneration(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.CreateTextureGenerationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Prompt == nil || *req.Prompt == "" {
+		writeError(w, r, http.StatusBadRequest, "bad-request", "Prompt is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.ModelAssetID != nil {
+		if _, ok := s.modelAssets[*req.ModelAssetID]; !ok {
+			notFound(w, r, "Model asset")
+			return
+		}
+	}
+	if !s.charge(CostTextureGeneration) {
+		insufficientCredits(w, r)
+		return
+	}
+	g := s.addGeneration(leonardo.CreateGenerationRequest{
+		Prompt:         *req.Prompt,
+		NegativePrompt: req.NegativePrompt,
+		Seed:           req.Seed,
+	}, CostTextureGeneration, true)
+
+	writeJSON(w, map[string]interface{}{
+		"textureGenerationJob": map[string]interface{}{"id": g.id, "apiCreditCost": CostTextureGeneration},
+	})
+}
+
+func (s *Server) createMotionGeneration(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.charge(CostMotionGeneration) {
+		insufficientCredits(w, r)
+		return
+	}
+	g := s.addGeneration(leonardo.CreateGenerationRequest{Prompt: "motion", NumImages: leonardo.Ptr(1)}, CostMotionGeneration, false)
+	writeJSON(w, map[string]interface{}{
+		"generationId": g.id,
+		"status":       leonardo.GenerationStatusPending,
+		"details":      map[string]interface{}{"apiCreditCost": CostMotionGeneration},
+	})
+}
+
+// Variations
+
+func (s *Server) createVariation(transform leonardo.TransformType, jobKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req leonardo.VariationRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		imageID := req.ID
+		if req.IsVariation != nil && *req.IsVariation {
+			v, ok := s.variations[req.ID]
+			if !ok {
+				notFound(w, r, "Variation")
+				return
+			}
+			imageID = v.imageID
+		}
+		img, ok := s.images[imageID]
+		if !ok {
+			notFound(w, r, "Image")
+			return
+		}
+		if !s.charge(CostVariation) {
+			insufficientCredits(w, r)
+			return
+		}
+		v := &variation{id: s.newID(), imageID: imageID, job: s.newJob(), transform: transform}
+		s.variations[v.id] = v
+		img.variationIDs = append(img.variationIDs, v.id)
+
+		writeJSON(w, map[string]interface{}{
+			jobKey: map[string]interface{}{"id": v.id, "apiCreditCost": CostVariation},
+		})
+	}
+}
+
+func (s *Server) universalUpscaler(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.UniversalUpscalerRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.ImageURL == "" {
+		writeError(w, r, http.StatusBadRequest, "bad-request", "image_url is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.charge(CostUniversalUpscaler) {
+		insufficientCredits(w, r)
+		return
+	}
+	id := s.newID()
+	writeJSON(w, map[string]interface{}{"upscaled_image_url": s.URL + "/files/" + id + ".png"})
+}
+
+func (s *Server) getVariation(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.variations[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Variation")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"generated_image_variation_generic": []interface{}{s.variationJSON(v)},
+	})
+}
+
+// variationJSON renders a variation. The caller must hold s.mu.
+func (s *Server) variationJSON(v *variation) map[string]interface{} {
+	status := s.status(v.job)
+	var url interface{}
+	if status == leonardo.GenerationStatusComplete {
+		url = s.URL + "/files/" + v.id + ".png"
+	}
+	return map[string]interface{}{
+		"id":            v.id,
+		"createdAt":     formatTime(v.job.createdAt),
+		"status":        status,
+		"transformType": v.transform,
+		"url":           url,
+	}
+}
+
+// Init images
+
+// presign returns a fake presigned S3 upload for key. The caller must hold s.mu.
+func (s *Server) presign(key string) (string, map[string]string) {
+	return s.URL + "/s3/upload", map[string]string{"key": key}
+}
+
+func (s *Server) uploadInitImage(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	img := &initImage{id: s.newID(), createdAt: s.now()}
+	img.key = "init-images/" + img.id + ".png"
+	s.initImages[img.id] = img
+	url, fields := s.presign(img.key)
+	fieldsJSON, _ := json.Marshal(fields)
+
+	writeJSON(w, map[string]interface{}{
+		"uploadInitImageId": img.id,
+		"message":           "Upload the image to the presigned URL.",
+		"uploadInitImage": map[string]interface{}{
+			"id":     img.id,
+			"key":    img.key,
+			"url":    url,
+			"fields": string(fieldsJSON),
+		},
+	})
+}
+
+func (s *Server) getInitImage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	img, ok := s.initImages[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Init image")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"init_images_by_pk": map[string]interface{}{
+			"id":        img.id,
+			"createdAt": formatTime(img.createdAt),
+			"url":       s.URL + "/s3/" + img.key,
+		},
+	})
+}
+
+func (s *Server) deleteInitImage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	img, ok := s.initImages[id]
+	if !ok {
+		notFound(w, r, "Init image")
+		return
+	}
+	delete(s.objects, img.key)
+	delete(s.initImages, id)
+	writeJSON(w, map[string]interface{}{"delete_init_images_by_pk": map[string]interface{}{"id": id}})
+}
+
+func (s *Server) uploadCanvasInitImage(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.UploadCanvasInitAndMaskImageRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	initImg := &initImage{id: s.newID(), createdAt: s.now()}
+	initImg.key = "canvas/" + initImg.id + "." + req.InitExtension
+	maskImg := &initImage{id: s.newID(), createdAt: s.now()}
+	maskImg.key = "canvas/" + maskImg.id + "." + req.MaskExtension
+	s.initImages[initImg.id] = initImg
+	s.initImages[maskImg.id] = maskImg
+	initURL, initFields := s.presign(initImg.key)
+	maskURL, maskFields := s.presign(maskImg.key)
+	initFieldsJSON, _ := json.Marshal(initFields)
+	maskFieldsJSON, _ := json.Marshal(maskFields)
+
+	writeJSON(w, map[string]interface{}{
+		"uploadCanvasInitImage": map[string]interface{}{
+			"initFields":  string(initFieldsJSON),
+			"initImageId": initImg.id,
+			"initKey":     initImg.key,
+			"initUrl":     initURL,
+			"maskFields":  string(maskFieldsJSON),
+			"maskImageId": maskImg.id,
+			"maskKey":     maskImg.key,
+			"maskUrl":     maskURL,
+		},
+	})
+}
+
+// Datasets
+
+func (s *Server) createDataset(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.CreateDatasetRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, r, http.StatusBadRequest, "bad-request", "Name is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := &dataset{id: s.newID(), name: req.Name, description: req.Description, createdAt: s.now(), updatedAt: s.now()}
+	s.datasets[d.id] = d
+	writeJSON(w, map[string]interface{}{"insert_datasets_one": map[string]interface{}{"id": d.id}})
+}
+
+func (s *Server) getDataset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Dataset")
+		return
+	}
+	images := []map[string]interface{}{}
+	for _, img := range d.images {
+		images = append(images, map[string]interface{}{"id": img.id, "url": img.url, "createdAt": formatTime(img.createdAt)})
+	}
+	writeJSON(w, map[string]interface{}{
+		"datasets_by_pk": map[string]interface{}{
+			"id":             d.id,
+			"name":           d.name,
+			"description":    d.description,
+			"createdAt":      formatTime(d.createdAt),
+			"updatedAt":      formatTime(d.updatedAt),
+			"dataset_images": images,
+		},
+	})
+}
+
+func (s *Server) deleteDataset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	if _, ok := s.datasets[id]; !ok {
+		notFound(w, r, "Dataset")
+		return
+	}
+	delete(s.datasets, id)
+	writeJSON(w, map[string]interface{}{"delete_datasets_by_pk": map[string]interface{}{"id": id}})
+}
+
+func (s *Server) uploadDatasetImage(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.UploadDatasetImageRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Dataset")
+		return
+	}
+	img := datasetImage{id: s.newID(), createdAt: s.now()}
+	key := "datasets/" + d.id + "/" + img.id + "." + req.Extension
+	img.url = s.URL + "/s3/" + key
+	d.images = append(d.images, img)
+	d.updatedAt = s.now()
+	url, fields := s.presign(key)
+
+	writeJSON(w, map[string]interface{}{
+		"uploadDatasetImage": map[string]interface{}{"id": img.id, "key": key, "url": url, "fields": fields},
+	})
+}
+
+func (s *Server) uploadGeneratedImageToDataset(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.UploadGeneratedImageRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Dataset")
+		return
+	}
+	if _, ok := s.images[req.GeneratedImageID]; !ok {
+		notFound(w, r, "Generated image")
+		return
+	}
+	img := datasetImage{id: s.newID(), url: s.URL + "/files/" + req.GeneratedImageID + ".png", createdAt: s.now()}
+	d.images = append(d.images, img)
+	d.updatedAt = s.now()
+	writeJSON(w, map[string]interface{}{"uploadDatasetImageFromGen": map[string]interface{}{"id": img.id}})
+}
+
+// Models
+
+func (s *Server) trainCustomModel(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.TrainCustomModelRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.datasets[req.DatasetID]; !ok {
+		notFound(w, r, "Dataset")
+		return
+	}
+	if !s.charge(CostModelTraining) {
+		insufficientCredits(w, r)
+		return
+	}
+	m := &customModel{id: s.newID(), job: s.newJob(), req: req, updatedAt: s.now()}
+	s.customModels[m.id] = m
+	writeJSON(w, map[string]interface{}{
+		"sdTrainingJob": map[string]interface{}{"customModelId": m.id, "apiCreditCost": CostModelTraining},
+	})
+}
+
+func (s *Server) getCustomModel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.customModels[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Custom model")
+		return
+	}
+	resolution := 512
+	if m.req.Resolution != nil {
+		resolution = *m.req.Resolution
+	}
+	writeJSON(w, map[string]interface{}{
+		"custom_models_by_pk": map[string]interface{}{
+			"id":             m.id,
+			"name":           m.req.Name,
+			"description":    m.req.Description,
+			"instancePrompt": m.req.InstancePrompt,
+			"modelHeight":    resolution,
+			"modelWidth":     resolution,
+			"public":         false,
+			"sdVersion":      m.req.SDVersion,
+			"status":         s.status(m.job),
+			"type":           m.req.ModelType,
+			"createdAt":      formatTime(m.job.createdAt),
+			"updatedAt":      formatTime(m.updatedAt),
+		},
+	})
+}
+
+func (s *Server) updateCustomModel(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.UpdateCustomModelRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.customModels[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Custom model")
+		return
+	}
+	if req.Name != nil {
+		m.req.Name = *req.Name
+	}
+	if req.Description != nil {
+		m.req.Description = req.Description
+	}
+	m.updatedAt = s.now()
+	writeJSON(w, map[string]interface{}{
+		"updated_custom_models_by_pk": map[string]interface{}{"id": m.id, "name": m.req.Name, "description": m.req.Description},
+	})
+}
+
+func (s *Server) deleteCustomModel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	if _, ok := s.customModels[id]; !ok {
+		notFound(w, r, "Custom model")
+		return
+	}
+	delete(s.customModels, id)
+	writeJSON(w, map[string]interface{}{"delete_custom_models_by_pk": map[string]interface{}{"id": id}})
+}
+
+func (s *Server) listPlatformModels(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	models := PlatformModels
+	if offset > len(models) {
+		offset = len(models)
+	}
+	models = models[offset:]
+	if limit > 0 && limit < len(models) {
+		models = models[:limit]
+	}
+	writeJSON(w, map[string]interface{}{"custom_models": models})
+}
+
+// 3D model assets
+
+func (s *Server) upload3DModel(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.Upload3DModelRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ext := "obj"
+	if req.ModelExtension != nil {
+		ext = *req.ModelExtension
+	}
+	m := &modelAsset{id: s.newID(), name: req.Name, createdAt: s.now()}
+	m.key = "models-3d/" + m.id + "." + ext
+	s.modelAssets[m.id] = m
+	url, fields := s.presign(m.key)
+	fieldsJSON, _ := json.Marshal(fields)
+
+	writeJSON(w, map[string]interface{}{
+		"uploadModelAsset": map[string]interface{}{
+			"modelFields": string(fieldsJSON),
+			"modelId":     m.id,
+			"modelKey":    m.key,
+			"modelUrl":    url,
+		},
+	})
+}
+
+// modelAssetJSON renders a 3D model asset. The caller must hold s.mu.
+func (s *Server) modelAssetJSON(m *modelAsset) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        m.id,
+		"name":      m.name,
+		"meshUrl":   s.URL + "/s3/" + m.key,
+		"userId":    UserID,
+		"createdAt": formatTime(m.createdAt),
+		"updatedAt": formatTime(m.createdAt),
+	}
+}
+
+func (s *Server) get3DModelsByUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assets := []map[string]interface{}{}
+	if r.PathValue("userId") == UserID {
+		for _, m := range s.modelAssets {
+			assets = append(assets, s.modelAssetJSON(m))
+		}
+	}
+	writeJSON(w, map[string]interface{}{"model_assets": assets})
+}
+
+func (s *Server) get3DModel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.modelAssets[r.PathValue("id")]
+	if !ok {
+		notFound(w, r, "Model asset")
+		return
+	}
+	writeJSON(w, map[string]interface{}{"model_assets_by_pk": s.modelAssetJSON(m)})
+}
+
+func (s *Server) delete3DModel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	m, ok := s.modelAssets[id]
+	if !ok {
+		notFound(w, r, "Model asset")
+		return
+	}
+	delete(s.objects, m.key)
+	delete(s.modelAssets, id)
+	writeJSON(w, map[string]interface{}{"delete_model_assets_by_pk": map[string]interface{}{"id": id}})
+}
+
+// Elements, prompts and user
+
+func (s *Server) listElements(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, leonardo.ListElementsResponse{Loras: Elements})
+}
+
+func (s *Server) randomPrompt(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.charge(CostPrompt) {
+		insufficientCredits(w, r)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"promptGeneration": map[string]interface{}{
+			"prompt":        fmt.Sprintf("A lighthouse on a cliff at dusk, variation %d", s.nextSeq()),
+			"apiCreditCost": CostPrompt,
+		},
+	})
+}
+
+func (s *Server) improvePrompt(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.ImprovePromptRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Prompt == nil || strings.TrimSpace(*req.Prompt) == "" {
+		writeError(w, r, http.StatusBadRequest, "bad-request", "Prompt is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.charge(CostPrompt) {
+		insufficientCredits(w, r)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"promptGeneration": map[string]interface{}{
+			"prompt":        *req.Prompt + ", highly detailed, dramatic lighting",
+			"apiCreditCost": CostPrompt,
+		},
+	})
+}
+
+func (s *Server) me(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	renewal := formatTime(s.now().AddDate(0, 1, 0))
+	writeJSON(w, map[string]interface{}{
+		"user_details": []interface{}{
+			map[string]interface{}{
+				"user":                    map[string]interface{}{"id": UserID, "username": Username},
+				"apiConcurrencySlots":     10,
+				"apiPaidTokens":           0,
+				"apiSubscriptionTokens":   s.credits,
+				"apiPlanTokenRenewalDate": renewal,
+				"paidTokens":              0,
+				"subscriptionGptTokens":   0,
+				"subscriptionModelTokens": 0,
+				"subscriptionTokens":      0,
+				"tokenRenewalDate":        renewal,
+			},
+		},
+	})
+}
+
+func (s *Server) calculateCost(w http.ResponseWriter, r *http.Request) {
+	var req leonardo.CalculateAPICostRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	cost := 0
+	switch req.Service {
+	case leonardo.PricingServiceTypeImageGeneration:
+		n := 4
+		if req.ServiceParams != nil && req.ServiceParams.ImageGeneration != nil && req.ServiceParams.ImageGeneration.NumImages != nil {
+			n = *req.ServiceParams.ImageGeneration.NumImages
+		}
+		cost = n * CostPerImage
+	case leonardo.PricingServiceTypeModelTraining:
+		cost = CostModelTraining
+	case leonardo.PricingServiceTypeTextureGeneration:
+		cost = CostTextureGeneration
+	case leonardo.PricingServiceTypeMotionGeneration:
+		cost = CostMotionGeneration
+	case leonardo.PricingServiceTypeUniversalUpscaler, leonardo.PricingServiceTypeUniversalUpscalerUltra:
+		cost = CostUniversalUpscaler
+	default:
+		writeError(w, r, http.StatusBadRequest, "bad-request", "Invalid service type provided.")
+		return
+	}
+	writeJSON(w, map[string]interface{}{"calculateProductionApiServiceCost": map[string]interface{}{"cost": cost}})
+}
+
+// Fake S3 and file hosting
+
+func (s *Server) s3Upload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key := r.FormValue("key")
+	file, _, err := r.FormFile("file")
+	if key == "" || err != nil {
+		http.Error(w, "key and file are required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.objects[key] = data
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) s3Object(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.Object(r.PathValue("key"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(data)
+}
+
+func (s *Server) file(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(r.PathValue("name"), ".png")
+	seed := 0
+	for _, c := range name {
+		seed = seed*31 + int(c)
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(placeholderPNG(seed))
+}
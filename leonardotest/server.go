@@ -0,0 +1,280 @@
+// Package leonardotest provides a stateful, in-memory fake of the Leonardo.ai API
+// for testing code built on github.com/emmaly/leonardo.
+//
+// The fake keeps generations, variations, init images, datasets, custom models,
+// 3D model assets and texture jobs in memory, moves jobs from PENDING to COMPLETE
+// after a configurable delay, deducts credits for paid operations, serves the
+// generated images, and accepts uploads on a fake presigned S3 endpoint.
+//
+//	srv := leonardotest.NewServer()
+//	defer srv.Close()
+//	client := srv.Client()
+package leonardotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emmaly/leonardo"
+)
+
+// DefaultCredits is the API token balance a new Server starts with.
+const DefaultCredits = 10000
+
+// UserID and Username identify the single user of the fake API.
+const (
+	UserID   = "00000000-0000-4000-8000-000000000001"
+	Username = "leonardotest"
+)
+
+// Failure describes an injected failure. Requests whose method and path match are
+// answered with StatusCode instead of being served, Times times (0 means forever).
+type Failure struct {
+	Method     string // empty matches any method
+	Path       string // matched as a prefix of the request path
+	StatusCode int
+	Message    string
+	Times      int
+}
+
+// Server is an in-memory fake of the Leonardo.ai API.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	now           func() time.Time
+	completeAfter time.Duration
+	credits       int
+	spent         int
+	seq           int
+	failures      []*Failure
+	failNextJobs  int
+
+	generations  map[string]*generation
+	images       map[string]*generatedImage
+	variations   map[string]*variation
+	initImages   map[string]*initImage
+	datasets     map[string]*dataset
+	customModels map[string]*customModel
+	modelAssets  map[string]*modelAsset
+	objects      map[string][]byte
+}
+
+// NewServer starts a fake Leonardo.ai API server. Jobs complete immediately until
+// SetCompleteAfter is called. Close the server when done.
+func NewServer() *Server {
+	s := &Server{
+		now:          time.Now,
+		credits:      DefaultCredits,
+		generations:  map[string]*generation{},
+		images:       map[string]*generatedImage{},
+		variations:   map[string]*variation{},
+		initImages:   map[string]*initImage{},
+		datasets:     map[string]*dataset{},
+		customModels: map[string]*customModel{},
+		modelAssets:  map[string]*modelAsset{},
+		objects:      map[string][]byte{},
+	}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// Client returns a leonardo.Client configured to talk to the fake server.
+func (s *Server) Client() *leonardo.Client {
+	c := leonardo.NewClient("leonardotest-api-key")
+	c.BaseURL = s.URL
+	c.HTTPClient = s.Server.Client()
+	return c
+}
+
+// SetCompleteAfter sets how long jobs stay PENDING before they complete.
+func (s *Server) SetCompleteAfter(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completeAfter = d
+}
+
+// SetClock replaces the clock used to timestamp and complete jobs.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// SetCredits sets the remaining API token balance.
+func (s *Server) SetCredits(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credits = n
+}
+
+// Credits returns the remaining API token balance.
+func (s *Server) Credits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.credits
+}
+
+// Spent returns the total API credits charged so far.
+func (s *Server) Spent() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spent
+}
+
+// Fail injects a failure for matching requests.
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.Message == "" {
+		f.Message = http.StatusText(f.StatusCode)
+	}
+	s.failures = append(s.failures, &f)
+}
+
+// FailJobs makes the next n submitted jobs end with status FAILED instead of COMPLETE.
+func (s *Server) FailJobs(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNextJobs = n
+}
+
+// Object returns the bytes uploaded to the fake S3 endpoint under key.
+func (s *Server) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.objects[key]
+	return b, ok
+}
+
+// serve authenticates the request and applies injected failures before routing.
+func (s *Server) serve(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Presigned S3 and file URLs are not authenticated.
+		if !strings.HasPrefix(r.URL.Path, "/s3/") && !strings.HasPrefix(r.URL.Path, "/files/") {
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") || r.Header.Get("Authorization") == "Bearer " {
+				writeError(w, r, http.StatusUnauthorized, "invalid-api-key", "Invalid API key.")
+				return
+			}
+		}
+		if f := s.failure(r); f != nil {
+			writeError(w, r, f.StatusCode, "injected-failure", f.Message)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// failure returns the injected failure matching the request, if any.
+func (s *Server) failure(r *http.Request) *Failure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.failures {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// charge deducts cost credits, reporting false when the balance is insufficient.
+// The caller must hold s.mu.
+func (s *Server) charge(cost int) bool {
+	if cost > s.credits {
+		return false
+	}
+	s.credits -= cost
+	s.spent += cost
+	return true
+}
+
+// newID returns a deterministic UUID-shaped identifier. The caller must hold s.mu.
+func (s *Server) newID() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextSeq())
+}
+
+// nextSeq advances the sequence that IDs and prompts are numbered by. The
+// caller must hold s.mu.
+func (s *Server) nextSeq() int {
+	s.seq++
+	return s.seq
+}
+
+// newJob records the submission time and fate of a job. The caller must hold s.mu.
+func (s *Server) newJob() job {
+	j := job{createdAt: s.now()}
+	if s.failNextJobs > 0 {
+		s.failNextJobs--
+		j.fail = true
+	}
+	return j
+}
+
+// job tracks the PENDING→COMPLETE/FAILED lifecycle shared by every asynchronous resource.
+type job struct {
+	createdAt time.Time
+	fail      bool
+}
+
+// status returns the job status at the current time. The caller must hold s.mu.
+func (s *Server) status(j job) leonardo.GenerationStatus {
+	if s.now().Sub(j.createdAt) < s.completeAfter {
+		return leonardo.GenerationStatusPending
+	}
+	if j.fail {
+		return leonardo.GenerationStatusFailed
+	}
+	return leonardo.GenerationStatusComplete
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(leonardo.APIErrorResponse{
+		Code:    code,
+		Message: message,
+		Path:    r.URL.Path,
+	})
+}
+
+// placeholderPNG renders a small solid-color PNG derived from the seed.
+func placeholderPNG(seed int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	c := color.RGBA{R: uint8(seed * 67), G: uint8(seed * 131), B: uint8(seed * 197), A: 255}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
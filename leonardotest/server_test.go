@@ -0,0 +1,212 @@
+package leonardotest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emmaly/leonardo"
+)
+
+// fakeClock is a manually advanced clock for SetClock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// TestGenerationLifecycle tests the PENDING→COMPLETE transition and credit accounting.
+func TestGenerationLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	srv.SetClock(clock.Now)
+	srv.SetCompleteAfter(10 * time.Second)
+	client := srv.Client()
+	ctx := context.Background()
+
+	job, err := client.Images.CreateImageGeneration(ctx, leonardo.CreateGenerationRequest{
+		Prompt:    "A lighthouse at dusk.",
+		NumImages: leonardo.Ptr(3),
+	})
+	if err != nil {
+		t.Fatalf("CreateImageGeneration failed: %v", err)
+	}
+	if cost := *job.SDGenerationJob.APICreditCost; cost != 3*CostPerImage {
+		t.Errorf("Expected APICreditCost %d, got %d", 3*CostPerImage, cost)
+	}
+	if srv.Credits() != DefaultCredits-3*CostPerImage {
+		t.Errorf("Expected %d credits left, got %d", DefaultCredits-3*CostPerImage, srv.Credits())
+	}
+
+	id := *job.SDGenerationJob.GenerationID
+	gen, err := client.Images.GetImageGeneration(ctx, id)
+	if err != nil {
+		t.Fatalf("GetImageGeneration failed: %v", err)
+	}
+	if *gen.GenerationsByPK.Status != leonardo.GenerationStatusPending {
+		t.Errorf("Expected status PENDING, got %s", *gen.GenerationsByPK.Status)
+	}
+	if len(gen.GenerationsByPK.GeneratedImages) != 0 {
+		t.Errorf("Expected no images while pending, got %d", len(gen.GenerationsByPK.GeneratedImages))
+	}
+
+	clock.Advance(10 * time.Second)
+	gen, err = client.Images.GetImageGeneration(ctx, id)
+	if err != nil {
+		t.Fatalf("GetImageGeneration failed: %v", err)
+	}
+	if *gen.GenerationsByPK.Status != leonardo.GenerationStatusComplete {
+		t.Errorf("Expected status COMPLETE, got %s", *gen.GenerationsByPK.Status)
+	}
+	if len(gen.GenerationsByPK.GeneratedImages) != 3 {
+		t.Fatalf("Expected 3 images, got %d", len(gen.GenerationsByPK.GeneratedImages))
+	}
+	if gen.GenerationsByPK.Prompt != "A lighthouse at dusk." {
+		t.Errorf("Expected prompt to round-trip, got %q", gen.GenerationsByPK.Prompt)
+	}
+
+	// Images are served by the fake
+	resp, err := http.Get(*gen.GenerationsByPK.GeneratedImages[0].URL)
+	if err != nil {
+		t.Fatalf("Downloading image failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/png" {
+		t.Errorf("Unexpected image response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// Variations attach to the generated image
+	imageID := *gen.GenerationsByPK.GeneratedImages[0].ID
+	upscale, err := client.Variation.CreateUpscaleVariation(ctx, imageID)
+	if err != nil {
+		t.Fatalf("CreateUpscaleVariation failed: %v", err)
+	}
+	clock.Advance(10 * time.Second)
+	variation, err := client.Variation.GetVariation(ctx, *upscale.SdUpscaleJob.ID)
+	if err != nil {
+		t.Fatalf("GetVariation failed: %v", err)
+	}
+	if len(variation.GeneratedImageVariationGeneric) != 1 || *variation.GeneratedImageVariationGeneric[0].Status != "COMPLETE" {
+		t.Errorf("Expected a completed variation, got %+v", variation.GeneratedImageVariationGeneric)
+	}
+
+	// Deleting removes the generation
+	if _, err := client.Images.DeleteGeneration(ctx, id); err != nil {
+		t.Fatalf("DeleteGeneration failed: %v", err)
+	}
+	_, err = client.Images.GetImageGeneration(ctx, id)
+	var apiErr *leonardo.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 APIError after delete, got %v", err)
+	}
+}
+
+// TestFailureInjection tests injected HTTP failures, FAILED jobs and exhausted credits.
+func TestFailureInjection(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	srv.Fail(Failure{Method: "POST", Path: "/generations", StatusCode: http.StatusTooManyRequests, Times: 1})
+	req := leonardo.CreateGenerationRequest{Prompt: "A storm over the sea.", NumImages: leonardo.Ptr(1)}
+	_, err := client.Images.CreateImageGeneration(ctx, req)
+	var apiErr *leonardo.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 APIError, got %v", err)
+	}
+
+	srv.FailJobs(1)
+	job, err := client.Images.CreateImageGeneration(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateImageGeneration failed after the injected failure was used up: %v", err)
+	}
+	gen, err := client.Images.GetImageGeneration(ctx, *job.SDGenerationJob.GenerationID)
+	if err != nil {
+		t.Fatalf("GetImageGeneration failed: %v", err)
+	}
+	if *gen.GenerationsByPK.Status != leonardo.GenerationStatusFailed {
+		t.Errorf("Expected status FAILED, got %s", *gen.GenerationsByPK.Status)
+	}
+
+	srv.SetCredits(1)
+	_, err = client.Images.CreateImageGeneration(ctx, req)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusPaymentRequired {
+		t.Errorf("Expected 402 APIError, got %v", err)
+	}
+
+	user, err := client.User.GetUserInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetUserInfo failed: %v", err)
+	}
+	if *user.UserDetails[0].APISubscriptionTokens != 1 {
+		t.Errorf("Expected 1 API subscription token, got %d", *user.UserDetails[0].APISubscriptionTokens)
+	}
+}
+
+// TestPresignedUpload tests the fake presigned S3 endpoint used by dataset uploads.
+func TestPresignedUpload(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	ds, err := client.Datasets.CreateDataset(ctx, leonardo.CreateDatasetRequest{Name: "Portraits"})
+	if err != nil {
+		t.Fatalf("CreateDataset failed: %v", err)
+	}
+	datasetID := *ds.InsertDatasetsOne.ID
+
+	upload, err := client.Datasets.UploadDatasetImage(ctx, datasetID, leonardo.UploadDatasetImageRequest{Extension: "png"})
+	if err != nil {
+		t.Fatalf("UploadDatasetImage failed: %v", err)
+	}
+
+	imagePath := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(imagePath, placeholderPNG(1), 0o644); err != nil {
+		t.Fatalf("Writing image failed: %v", err)
+	}
+	if err := leonardo.UploadImageToS3(*upload.UploadDatasetImage.URL, upload.UploadDatasetImage.Fields, imagePath); err != nil {
+		t.Fatalf("UploadImageToS3 failed: %v", err)
+	}
+	if _, ok := srv.Object(*upload.UploadDatasetImage.Key); !ok {
+		t.Error("Expected the uploaded object to be stored")
+	}
+
+	got, err := client.Datasets.GetDataset(ctx, datasetID)
+	if err != nil {
+		t.Fatalf("GetDataset failed: %v", err)
+	}
+	if len(got.DatasetsByPk.DatasetImages) != 1 {
+		t.Errorf("Expected 1 dataset image, got %d", len(got.DatasetsByPk.DatasetImages))
+	}
+
+	train, err := client.Models.TrainCustomModel(ctx, leonardo.TrainCustomModelRequest{
+		DatasetID:      datasetID,
+		InstancePrompt: "a portrait",
+		Name:           "Portrait Model",
+	})
+	if err != nil {
+		t.Fatalf("TrainCustomModel failed: %v", err)
+	}
+	if *train.SDTrainingJob.APICreditCost != CostModelTraining {
+		t.Errorf("Expected APICreditCost %d, got %d", CostModelTraining, *train.SDTrainingJob.APICreditCost)
+	}
+}
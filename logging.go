@@ -0,0 +1,140 @@
+package leonardo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// logDataURLPrefix is how many characters of a base64 data URL payload are kept in logs.
+const logDataURLPrefix = 32
+
+var dataURLPattern = regexp.MustCompile(`(data:[a-zA-Z0-9.+/-]+;base64,)([A-Za-z0-9+/=]+)`)
+
+// logRequest emits one record describing a completed HTTP request.
+func (c *Client) logRequest(req *http.Request, call *Call, status int, latency time.Duration, reqBody []byte, respBody *bytes.Buffer, v interface{}, err error) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", c.relativePath(req)),
+		slog.Duration("latency", latency),
+	}
+	if call != nil {
+		attrs = append(attrs,
+			slog.String("service", call.Service),
+			slog.String("operation", call.Operation),
+			slog.Int("attempt", call.attempt),
+		)
+	}
+	if status != 0 {
+		attrs = append(attrs, slog.Int("status", status))
+	} else if c.DryRun != nil && c.DryRun.intercepts(req) {
+		attrs = append(attrs, slog.Bool("dry_run", true))
+	}
+	if err == nil {
+		if id := responseJobID(v); id != "" {
+			attrs = append(attrs, slog.String("job_id", id))
+		} else if call != nil && call.ID != "" {
+			attrs = append(attrs, slog.String("job_id", call.ID))
+		}
+	}
+	if c.LogBodies {
+		sentKey, _ := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if len(reqBody) > 0 {
+			attrs = append(attrs, slog.String("request_body", c.redact(reqBody, sentKey)))
+		}
+		if respBody != nil && respBody.Len() > 0 {
+			attrs = append(attrs, slog.String("response_body", c.redact(respBody.Bytes(), sentKey)))
+		}
+	}
+
+	level, msg := slog.LevelInfo, "leonardo request"
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		level, msg = slog.LevelError, "leonardo request failed"
+		if status != 0 {
+			level = slog.LevelWarn
+		}
+	}
+	c.Logger.LogAttrs(req.Context(), level, msg, attrs...)
+}
+
+// relativePath returns the request path relative to BaseURL, without the query.
+func (c *Client) relativePath(req *http.Request) string {
+	path := strings.TrimPrefix(req.URL.String(), c.BaseURL)
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// redact removes the client's API key and the key the request was sent with
+// from a body and truncates base64 data URLs.
+func (c *Client) redact(body []byte, sentKey string) string {
+	s := string(body)
+	for _, key := range []string{c.APIKey, sentKey} {
+		if key != "" {
+			s = strings.ReplaceAll(s, key, "[REDACTED]")
+		}
+	}
+	return dataURLPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := dataURLPattern.FindStringSubmatch(m)
+		if len(parts[2]) <= logDataURLPrefix {
+			return m
+		}
+		return fmt.Sprintf("%s%s...(%d bytes)", parts[1], parts[2][:logDataURLPrefix], len(parts[2]))
+	})
+}
+
+// peekRequestBody reads the request body without consuming it.
+func peekRequestBody(req *http.Request) []byte {
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil
+		}
+		defer rc.Close()
+		b, _ := io.ReadAll(rc)
+		return b
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	b, _ := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return b
+}
+
+// responseJobID returns the ID of the job or resource a response refers to, if any.
+func responseJobID(v interface{}) string {
+	var id *string
+	switch resp := v.(type) {
+	case *CreateGenerationResponse:
+		id = resp.SDGenerationJob.GenerationID
+	case *GetGenerationResponse:
+		id = resp.GenerationsByPK.ID
+	case *TrainCustomModelResponse:
+		id = resp.SDTrainingJob.CustomModelID
+	case *CreateTextureGenerationResponse:
+		id = resp.TextureGenerationJob.ID
+	case *UpscaleVariationResponse:
+		id = resp.SdUpscaleJob.ID
+	case *CreateUnzoomVariationResponse:
+		id = resp.SdUnzoomJob.ID
+	case *CreateNoBackgroundVariationResponse:
+		id = resp.SdNobgJob.ID
+	case *CreateSVDMotionGenerationResponse:
+		if resp.GenerationID != "" {
+			return resp.GenerationID
+		}
+	}
+	if id == nil {
+		return ""
+	}
+	return *id
+}
@@ -0,0 +1,123 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLogging tests the structured log records emitted for each request.
+func TestLogging(t *testing.T) {
+	imageData := "data:image/png;base64," + strings.Repeat("QUJD", 100)
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/generations-lcm":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"lcmGenerationJob": map[string]interface{}{"apiCreditCost": 1, "imageDataUrl": []string{imageData}},
+			})
+		case "/generations/gen-404":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(APIErrorResponse{Code: "not-found", Message: "Generation not found."})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	var logs bytes.Buffer
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+		Logger:     slog.New(slog.NewJSONHandler(&logs, nil)),
+		LogBodies:  true,
+	}
+	client.RealtimeCanvas = client.NewRealtimeCanvasService()
+	client.Images = client.NewImagesService()
+
+	ctx := context.Background()
+	_, err := client.RealtimeCanvas.CreateLCMGeneration(ctx, CreateLCMGenerationRequest{
+		ImageDataURL: imageData,
+		Prompt:       "sketch of a cat, key test-api-key",
+	})
+	if err != nil {
+		t.Fatalf("CreateLCMGeneration failed: %v", err)
+	}
+	_, err = client.Images.GetImageGeneration(ctx, "gen-404")
+	if err == nil {
+		t.Fatal("Expected error for missing generation, got nil")
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log records, got %d: %s", len(lines), logs.String())
+	}
+	if strings.Contains(logs.String(), "test-api-key") {
+		t.Error("Expected the API key to be redacted")
+	}
+	if strings.Contains(logs.String(), strings.Repeat("QUJD", 20)) {
+		t.Error("Expected base64 image data to be truncated")
+	}
+
+	var first, second map[string]interface{}
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+
+	if first["service"] != "RealtimeCanvas" || first["operation"] != "CreateLCMGeneration" {
+		t.Errorf("Unexpected service/operation: %v/%v", first["service"], first["operation"])
+	}
+	if first["path"] != "/generations-lcm" || first["status"] != float64(200) || first["attempt"] != float64(1) {
+		t.Errorf("Unexpected record: %v", first)
+	}
+	if _, ok := first["response_body"]; !ok {
+		t.Error("Expected the response body to be logged")
+	}
+
+	if second["level"] != "WARN" || second["status"] != float64(404) || second["job_id"] != nil {
+		t.Errorf("Unexpected error record: %v", second)
+	}
+}
+
+// TestLoggingPooledKey tests that the key supplied by a KeyProvider is redacted.
+func TestLoggingPooledKey(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ImprovePromptResponse{PromptGeneration: &PromptGeneration{Prompt: Ptr("A cat, key pooled-key.")}})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	var logs bytes.Buffer
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		Logger:     slog.New(slog.NewJSONHandler(&logs, nil)),
+		LogBodies:  true,
+	}
+	client.Prompt = client.NewPromptService()
+	client.KeyProvider = client.NewKeyPool("pooled-key")
+
+	if _, err := client.Prompt.ImprovePrompt(context.Background(), ImprovePromptRequest{Prompt: Ptr("A cat, key pooled-key.")}); err != nil {
+		t.Fatalf("ImprovePrompt failed: %v", err)
+	}
+	if strings.Contains(logs.String(), "pooled-key") {
+		t.Errorf("Expected the pooled key to be redacted, got %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "[REDACTED]") {
+		t.Errorf("Expected the request body to be logged, got %s", logs.String())
+	}
+}
@@ -0,0 +1,265 @@
+package leonardo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// GenerationToRequest reconstructs the request that produced gen from the
+// settings the API reports. Alchemy isn't reported, so it is left unset unless
+// PhotoReal requires it; PhotoReal is assumed to be v2 when a model is reported.
+func GenerationToRequest(gen GenerationDetails) CreateGenerationRequest {
+	req := CreateGenerationRequest{
+		Prompt:              gen.Prompt,
+		NegativePrompt:      gen.NegativePrompt,
+		ModelID:             gen.ModelID,
+		Width:               gen.Width,
+		Height:              gen.Height,
+		NumInferenceSteps:   gen.NumInferenceSteps,
+		GuidanceScale:       gen.GuidanceScale,
+		PhotoReal:           gen.PhotoReal,
+		PhotoRealStrength:   gen.PhotoRealStrength,
+		PresetStyle:         gen.PresetStyle,
+		PromptMagic:         gen.PromptMagic,
+		PromptMagicStrength: gen.PromptMagicStrength,
+		PromptMagicVersion:  gen.PromptMagicVersion,
+		Public:              gen.Public,
+		Scheduler:           gen.Scheduler,
+		SDVersion:           gen.SDVersion,
+		Seed:                gen.Seed,
+		Ultra:               gen.Ultra,
+	}
+	if n := len(gen.GeneratedImages); n > 0 {
+		req.NumImages = Ptr(n)
+	}
+	if deref(gen.PhotoReal) {
+		req.Alchemy = Ptr(true)
+		if gen.ModelID != nil {
+			req.PhotoRealVersion = Ptr(PhotoRealVersionV2)
+		} else {
+			req.PhotoRealVersion = Ptr(PhotoRealVersionV1)
+		}
+	}
+	for _, e := range gen.GenerationElements {
+		if e.Lora == nil || e.Lora.AKUUID == nil {
+			continue
+		}
+		req.Elements = append(req.Elements, ElementWeight{AKUUID: *e.Lora.AKUUID, Weight: deref(e.WeightApplied)})
+	}
+	return req
+}
+
+// ManifestVersion is the version of the GenerationManifest format.
+const ManifestVersion = 1
+
+// GenerationManifest is a portable record of a generation: the request to make
+// it again, the settings the API resolved, and checksums of the images it produced.
+type GenerationManifest struct {
+	ManifestVersion int                     `json:"manifestVersion"`
+	ClientVersion   string                  `json:"clientVersion"`
+	CreatedAt       Time                    `json:"createdAt"`
+	GenerationID    string                  `json:"generationId"`
+	Request         CreateGenerationRequest `json:"request"`
+	Settings        GenerationDetails       `json:"settings"`
+	Outputs         []ManifestOutput        `json:"outputs"`
+}
+
+// ManifestOutput is an image recorded in a GenerationManifest.
+type ManifestOutput struct {
+	ImageID string `json:"imageId"`
+	URL     string `json:"url"`
+	SHA256  string `json:"sha256"`
+	Size    int64  `json:"size"`
+}
+
+// ReadManifest decodes a GenerationManifest written by WriteManifest.
+func ReadManifest(r io.Reader) (*GenerationManifest, error) {
+	var m GenerationManifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("reading manifest failed: %w", err)
+	}
+	if m.ManifestVersion > ManifestVersion {
+		return nil, fmt.Errorf("reading manifest failed: unsupported version %d", m.ManifestVersion)
+	}
+	return &m, nil
+}
+
+// WriteManifest encodes m as indented JSON.
+func WriteManifest(w io.Writer, m *GenerationManifest) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// clientVersion returns the version of this module in the running binary.
+func clientVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/emmaly/leonardo" {
+				return dep.Version
+			}
+		}
+		if info.Main.Path == "github.com/emmaly/leonardo" {
+			return info.Main.Version
+		}
+	}
+	return "(devel)"
+}
+
+// NewManifest fetches a completed generation, downloads its images to checksum
+// them, and returns a manifest that can be replayed later.
+func (s *ImagesService) NewManifest(ctx context.Context, generationID string) (*GenerationManifest, error) {
+	resp, err := s.GetImageGeneration(ctx, generationID)
+	if err != nil {
+		return nil, err
+	}
+	if status := resp.Status(); status != GenerationStatusComplete {
+		return nil, fmt.Errorf("generation %s is %s, not COMPLETE", generationID, status)
+	}
+
+	outputs, err := s.checksumImages(ctx, resp.Images())
+	if err != nil {
+		return nil, err
+	}
+
+	settings := resp.GenerationsByPK
+	settings.GeneratedImages = nil
+	return &GenerationManifest{
+		ManifestVersion: ManifestVersion,
+		ClientVersion:   clientVersion(),
+		CreatedAt:       Time{Time: time.Now().UTC()},
+		GenerationID:    generationID,
+		Request:         GenerationToRequest(resp.GenerationsByPK),
+		Settings:        settings,
+		Outputs:         outputs,
+	}, nil
+}
+
+// checksumImages downloads images and returns their checksums.
+func (s *ImagesService) checksumImages(ctx context.Context, images []GeneratedImage) ([]ManifestOutput, error) {
+	outputs := make([]ManifestOutput, 0, len(images))
+	for _, img := range images {
+		h := sha256.New()
+		n, err := s.DownloadImage(ctx, deref(img.URL), h)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, ManifestOutput{
+			ImageID: deref(img.ID),
+			URL:     deref(img.URL),
+			SHA256:  hex.EncodeToString(h.Sum(nil)),
+			Size:    n,
+		})
+	}
+	return outputs, nil
+}
+
+// ManifestDifference is a setting or output that differs between a manifest and its replay.
+type ManifestDifference struct {
+	Field string `json:"field"`
+	Want  string `json:"want"`
+	Got   string `json:"got"`
+}
+
+// ReplayResult is the outcome of replaying a manifest.
+type ReplayResult struct {
+	// Manifest records the replayed generation.
+	Manifest *GenerationManifest
+
+	// Differences lists what differs from the original. It is empty when the
+	// replay reproduced the original exactly.
+	Differences []ManifestDifference
+}
+
+// Replay submits the manifest's request again, waits for it to complete, and
+// reports the settings and outputs that differ from the manifest.
+func (s *ImagesService) Replay(ctx context.Context, m *GenerationManifest) (*ReplayResult, error) {
+	created, err := s.CreateImageGeneration(ctx, m.Request)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.WaitForGeneration(ctx, created.GenerationID(), 0); err != nil {
+		return nil, err
+	}
+	replayed, err := s.NewManifest(ctx, created.GenerationID())
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayResult{Manifest: replayed, Differences: CompareManifests(m, replayed)}, nil
+}
+
+// CompareManifests returns the settings and outputs that differ between want and got.
+// Identifiers and timestamps are expected to differ and are not compared.
+func CompareManifests(want, got *GenerationManifest) []ManifestDifference {
+	var diffs []ManifestDifference
+
+	wv, gv := reflect.ValueOf(want.Settings), reflect.ValueOf(got.Settings)
+	for i := 0; i < wv.NumField(); i++ {
+		field := wv.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "id", "status", "createdAt", "generated_images":
+			continue
+		case "generation_elements":
+			w, g := elementsString(want.Settings.GenerationElements), elementsString(got.Settings.GenerationElements)
+			if w != g {
+				diffs = append(diffs, ManifestDifference{Field: "settings." + name, Want: w, Got: g})
+			}
+			continue
+		}
+		w, g := valueString(wv.Field(i)), valueString(gv.Field(i))
+		if w != g {
+			diffs = append(diffs, ManifestDifference{Field: "settings." + name, Want: w, Got: g})
+		}
+	}
+
+	if len(want.Outputs) != len(got.Outputs) {
+		diffs = append(diffs, ManifestDifference{
+			Field: "outputs",
+			Want:  fmt.Sprintf("%d images", len(want.Outputs)),
+			Got:   fmt.Sprintf("%d images", len(got.Outputs)),
+		})
+	}
+	for i := 0; i < len(want.Outputs) && i < len(got.Outputs); i++ {
+		if want.Outputs[i].SHA256 != got.Outputs[i].SHA256 {
+			diffs = append(diffs, ManifestDifference{
+				Field: fmt.Sprintf("outputs[%d].sha256", i),
+				Want:  want.Outputs[i].SHA256,
+				Got:   got.Outputs[i].SHA256,
+			})
+		}
+	}
+	return diffs
+}
+
+// valueString formats a settings field for comparison, dereferencing pointers.
+func valueString(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// elementsString formats applied elements for comparison, ignoring their IDs.
+func elementsString(elements []GenerationElement) string {
+	parts := make([]string, 0, len(elements))
+	for _, e := range elements {
+		var akUUID string
+		if e.Lora != nil {
+			akUUID = deref(e.Lora.AKUUID)
+		}
+		parts = append(parts, fmt.Sprintf("%s@%v", akUUID, deref(e.WeightApplied)))
+	}
+	return strings.Join(parts, ",")
+}
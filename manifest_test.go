@@ -0,0 +1,141 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestGenerationToRequest tests reconstructing a request from a fetched generation.
+func TestGenerationToRequest(t *testing.T) {
+	gen := GenerationDetails{
+		ID:                Ptr("gen-123"),
+		Prompt:            "A lighthouse.",
+		ModelID:           Ptr("aa77f04e-3eec-4034-9c07-d0f619684628"),
+		Width:             Ptr(1024),
+		Height:            Ptr(768),
+		Seed:              Ptr(42),
+		Scheduler:         Ptr(SchedulerLeonardo),
+		PhotoReal:         Ptr(true),
+		PhotoRealStrength: Ptr(0.5),
+		GenerationElements: []GenerationElement{
+			{ID: Ptr("el-1"), Lora: &Lora{AKUUID: Ptr("element-1")}, WeightApplied: Ptr(0.8)},
+		},
+		GeneratedImages: []GeneratedImage{{ID: Ptr("img-1")}, {ID: Ptr("img-2")}},
+	}
+
+	req := GenerationToRequest(gen)
+	if req.Prompt != "A lighthouse." || *req.Seed != 42 || *req.Scheduler != SchedulerLeonardo || *req.NumImages != 2 {
+		t.Errorf("Unexpected request: %+v", req)
+	}
+	if !*req.Alchemy || *req.PhotoRealVersion != PhotoRealVersionV2 {
+		t.Errorf("Expected PhotoReal v2 with Alchemy, got %+v", req)
+	}
+	if len(req.Elements) != 1 || req.Elements[0] != (ElementWeight{AKUUID: "element-1", Weight: 0.8}) {
+		t.Errorf("Unexpected elements: %+v", req.Elements)
+	}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Expected the reconstructed request to be valid, got %v", err)
+	}
+}
+
+// TestManifestReplay tests recording a manifest and replaying it.
+func TestManifestReplay(t *testing.T) {
+	var mu sync.Mutex
+	generations := map[string]CreateGenerationRequest{}
+	drift := false
+
+	// Mock server setup
+	var server *httptest.Server
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/generations" && r.Method == "POST":
+			var req CreateGenerationRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			id := fmt.Sprintf("gen-%d", len(generations)+1)
+			generations[id] = req
+			json.NewEncoder(w).Encode(CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr(id)}})
+		case strings.HasPrefix(r.URL.Path, "/generations/"):
+			id := strings.TrimPrefix(r.URL.Path, "/generations/")
+			req := generations[id]
+			json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{
+				ID:              Ptr(id),
+				Status:          Ptr(GenerationStatusComplete),
+				Prompt:          req.Prompt,
+				Seed:            req.Seed,
+				Scheduler:       req.Scheduler,
+				GeneratedImages: []GeneratedImage{{ID: Ptr("img-" + id), URL: Ptr(server.URL + "/images/" + id)}},
+			}})
+		case strings.HasPrefix(r.URL.Path, "/images/"):
+			req := generations[strings.TrimPrefix(r.URL.Path, "/images/")]
+			fmt.Fprintf(w, "image for seed %d", *req.Seed)
+			if drift {
+				w.Write([]byte(" (drifted)"))
+			}
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server = httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	ctx := context.Background()
+	generations["gen-0"] = CreateGenerationRequest{Prompt: "A lighthouse.", Seed: Ptr(42), Scheduler: Ptr(SchedulerDDIM)}
+	manifest, err := client.Images.NewManifest(ctx, "gen-0")
+	if err != nil {
+		t.Fatalf("NewManifest failed: %v", err)
+	}
+	if manifest.ManifestVersion != ManifestVersion || manifest.ClientVersion == "" || len(manifest.Outputs) != 1 {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+	if manifest.Outputs[0].Size != int64(len("image for seed 42")) || len(manifest.Outputs[0].SHA256) != 64 {
+		t.Errorf("Unexpected output: %+v", manifest.Outputs[0])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, manifest); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	manifest, err = ReadManifest(&buf)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+
+	replay, err := client.Images.Replay(ctx, manifest)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if len(replay.Differences) != 0 {
+		t.Errorf("Expected no differences, got %+v", replay.Differences)
+	}
+	if got := generations[replay.Manifest.GenerationID]; *got.Seed != 42 || *got.Scheduler != SchedulerDDIM {
+		t.Errorf("Expected the replay to resubmit the original settings, got %+v", got)
+	}
+
+	drift = true
+	replay, err = client.Images.Replay(ctx, manifest)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if len(replay.Differences) != 1 || replay.Differences[0].Field != "outputs[0].sha256" {
+		t.Errorf("Expected the output checksum to differ, got %+v", replay.Differences)
+	}
+}
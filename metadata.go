@@ -0,0 +1,419 @@
+package leonardo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ImageMetadata describes how an image was generated. It can be embedded in a
+// downloaded PNG or JPEG with WithImageMetadata and read back with ReadImageMetadata.
+type ImageMetadata struct {
+	Prompt         string
+	NegativePrompt string
+	Seed           *int
+	ModelID        string
+	PresetStyle    PresetStyle
+	GenerationID   string
+	ImageID        string
+}
+
+// ErrNoImageMetadata is returned by ReadImageMetadata for images without embedded metadata.
+var ErrNoImageMetadata = errors.New("image has no Leonardo metadata")
+
+// MetadataFromGeneration returns the metadata of image imageID of gen.
+func MetadataFromGeneration(gen *GetGenerationResponse, imageID string) ImageMetadata {
+	g := gen.GenerationsByPK
+	return ImageMetadata{
+		Prompt:         g.Prompt,
+		NegativePrompt: deref(g.NegativePrompt),
+		Seed:           g.Seed,
+		ModelID:        deref(g.ModelID),
+		PresetStyle:    deref(g.PresetStyle),
+		GenerationID:   deref(g.ID),
+		ImageID:        imageID,
+	}
+}
+
+// Metadata keys, used as PNG text keywords, XMP properties and JPEG comment keys.
+const (
+	metaPrompt         = "prompt"
+	metaNegativePrompt = "negative_prompt"
+	metaSeed           = "seed"
+	metaModelID        = "model_id"
+	metaPresetStyle    = "preset_style"
+	metaGenerationID   = "generation_id"
+	metaImageID        = "image_id"
+
+	// metaKeyPrefix namespaces PNG keywords and JPEG comment keys.
+	metaKeyPrefix = "leonardo:"
+
+	// xmpNamespace is the XMP namespace of the properties.
+	xmpNamespace = "https://leonardo.ai/ns/image/1.0/"
+)
+
+// fields returns the set metadata values in a fixed order.
+func (m ImageMetadata) fields() [][2]string {
+	var fields [][2]string
+	add := func(key, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{key, value})
+		}
+	}
+	add(metaPrompt, m.Prompt)
+	add(metaNegativePrompt, m.NegativePrompt)
+	if m.Seed != nil {
+		add(metaSeed, strconv.Itoa(*m.Seed))
+	}
+	add(metaModelID, m.ModelID)
+	add(metaPresetStyle, string(m.PresetStyle))
+	add(metaGenerationID, m.GenerationID)
+	add(metaImageID, m.ImageID)
+	return fields
+}
+
+// set sets the field named key, reporting whether key is known.
+func (m *ImageMetadata) set(key, value string) bool {
+	switch key {
+	case metaPrompt:
+		m.Prompt = value
+	case metaNegativePrompt:
+		m.NegativePrompt = value
+	case metaSeed:
+		if seed, err := strconv.Atoi(value); err == nil {
+			m.Seed = &seed
+		}
+	case metaModelID:
+		m.ModelID = value
+	case metaPresetStyle:
+		m.PresetStyle = PresetStyle(value)
+	case metaGenerationID:
+		m.GenerationID = value
+	case metaImageID:
+		m.ImageID = value
+	default:
+		return false
+	}
+	return true
+}
+
+// DownloadOption configures DownloadImage.
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	metadata *ImageMetadata
+}
+
+// WithImageMetadata embeds meta in the downloaded image: as tEXt (or, for
+// non-ASCII values, iTXt) chunks in a PNG, or as a comment and XMP packet in a
+// JPEG. Other formats are written unchanged.
+func WithImageMetadata(meta ImageMetadata) DownloadOption {
+	return func(o *downloadOptions) {
+		o.metadata = &meta
+	}
+}
+
+// EmbedImageMetadata returns a copy of the PNG or JPEG image data with meta
+// embedded; see WithImageMetadata. Other formats are returned unchanged.
+func EmbedImageMetadata(data []byte, meta ImageMetadata) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, pngSignature):
+		return embedPNG(data, meta)
+	case bytes.HasPrefix(data, jpegSOI):
+		return embedJPEG(data, meta)
+	}
+	return data, nil
+}
+
+// ReadImageMetadata extracts the metadata embedded by WithImageMetadata from a
+// PNG or JPEG image.
+func ReadImageMetadata(r io.Reader) (*ImageMetadata, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading image metadata failed: %w", err)
+	}
+	var meta *ImageMetadata
+	switch {
+	case bytes.HasPrefix(data, pngSignature):
+		meta, err = readPNG(data)
+	case bytes.HasPrefix(data, jpegSOI):
+		meta, err = readJPEG(data)
+	default:
+		return nil, errors.New("reading image metadata failed: not a PNG or JPEG image")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading image metadata failed: %w", err)
+	}
+	if meta == nil {
+		return nil, ErrNoImageMetadata
+	}
+	return meta, nil
+}
+
+// PNG
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// embedPNG inserts text chunks after the IHDR chunk.
+func embedPNG(data []byte, meta ImageMetadata) ([]byte, error) {
+	pos := len(pngSignature)
+	if len(data) < pos+8 || string(data[pos+4:pos+8]) != "IHDR" {
+		return nil, errors.New("embedding image metadata failed: PNG does not start with IHDR")
+	}
+	pos += 12 + int(binary.BigEndian.Uint32(data[pos:]))
+	if pos > len(data) {
+		return nil, errors.New("embedding image metadata failed: truncated PNG")
+	}
+
+	var chunks bytes.Buffer
+	for _, f := range meta.fields() {
+		keyword := metaKeyPrefix + f[0]
+		if isASCII(f[1]) {
+			writePNGChunk(&chunks, "tEXt", []byte(keyword+"\x00"+f[1]))
+		} else {
+			// keyword, null, uncompressed, compression method, empty language tag and translated keyword
+			writePNGChunk(&chunks, "iTXt", []byte(keyword+"\x00\x00\x00\x00\x00"+f[1]))
+		}
+	}
+
+	out := make([]byte, 0, len(data)+chunks.Len())
+	out = append(out, data[:pos]...)
+	out = append(out, chunks.Bytes()...)
+	return append(out, data[pos:]...), nil
+}
+
+func writePNGChunk(w *bytes.Buffer, typ string, data []byte) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	w.Write(length[:])
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	w.WriteString(typ)
+	w.Write(data)
+	binary.Write(w, binary.BigEndian, crc.Sum32())
+}
+
+// readPNG returns the metadata in the text chunks of a PNG, or nil if there is none.
+func readPNG(data []byte) (*ImageMetadata, error) {
+	var meta ImageMetadata
+	found := false
+	for pos := len(pngSignature); pos+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		typ := string(data[pos+4 : pos+8])
+		if pos+12+length > len(data) {
+			return nil, errors.New("truncated PNG")
+		}
+		body := data[pos+8 : pos+8+length]
+		pos += 12 + length
+
+		var keyword, value string
+		switch typ {
+		case "tEXt":
+			k, v, _ := bytes.Cut(body, []byte{0})
+			keyword, value = string(k), latin1ToUTF8(v)
+		case "iTXt":
+			k, rest, _ := bytes.Cut(body, []byte{0})
+			if len(rest) < 2 || rest[0] != 0 { // compressed text isn't written by embedPNG
+				continue
+			}
+			_, rest, _ = bytes.Cut(rest[2:], []byte{0}) // language tag
+			_, rest, _ = bytes.Cut(rest, []byte{0})     // translated keyword
+			keyword, value = string(k), string(rest)
+		case "IEND":
+			pos = len(data)
+			continue
+		default:
+			continue
+		}
+		if key, ok := strings.CutPrefix(keyword, metaKeyPrefix); ok && meta.set(key, value) {
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return &meta, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func latin1ToUTF8(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// JPEG
+
+var jpegSOI = []byte{0xFF, 0xD8}
+
+const (
+	jpegAPP0 = 0xE0
+	jpegAPP1 = 0xE1
+	jpegSOS  = 0xDA
+	jpegCOM  = 0xFE
+
+	// jpegMaxSegment is the largest payload of a JPEG segment.
+	jpegMaxSegment = 0xFFFF - 2
+)
+
+var xmpHeader = []byte("http://ns.adobe.com/xap/1.0/\x00")
+
+// embedJPEG inserts an XMP segment and a comment after the SOI and any leading
+// APP0 and APP1 segments, so that JFIF and Exif segments stay first.
+func embedJPEG(data []byte, meta ImageMetadata) ([]byte, error) {
+	pos := len(jpegSOI)
+	for len(data) >= pos+4 && data[pos] == 0xFF && (data[pos+1] == jpegAPP0 || data[pos+1] == jpegAPP1) {
+		pos += 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if pos > len(data) {
+			return nil, errors.New("embedding image metadata failed: truncated JPEG")
+		}
+	}
+
+	var comment strings.Builder
+	for _, f := range meta.fields() {
+		fmt.Fprintf(&comment, "%s%s=%s\n", metaKeyPrefix, f[0], strings.ReplaceAll(f[1], "\n", " "))
+	}
+	com := []byte(comment.String())
+	if len(com) > jpegMaxSegment {
+		com = com[:jpegMaxSegment]
+	}
+
+	xmp := append(append([]byte(nil), xmpHeader...), xmpPacket(meta)...)
+	if len(xmp) > jpegMaxSegment {
+		return nil, errors.New("embedding image metadata failed: XMP packet too large")
+	}
+
+	var segments bytes.Buffer
+	writeJPEGSegment(&segments, jpegAPP1, xmp)
+	writeJPEGSegment(&segments, jpegCOM, com)
+
+	out := make([]byte, 0, len(data)+segments.Len())
+	out = append(out, data[:pos]...)
+	out = append(out, segments.Bytes()...)
+	return append(out, data[pos:]...), nil
+}
+
+func writeJPEGSegment(w *bytes.Buffer, marker byte, data []byte) {
+	w.Write([]byte{0xFF, marker})
+	binary.Write(w, binary.BigEndian, uint16(len(data)+2))
+	w.Write(data)
+}
+
+// xmpPacket returns an XMP packet holding meta as properties of one rdf:Description.
+func xmpPacket(meta ImageMetadata) []byte {
+	var b bytes.Buffer
+	b.WriteString("<?xpacket begin=\"\uFEFF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">`)
+	b.WriteString(`<rdf:Description rdf:about="" xmlns:leonardo="` + xmpNamespace + `"`)
+	for _, f := range meta.fields() {
+		b.WriteString(" leonardo:" + f[0] + `="`)
+		xml.EscapeText(&b, []byte(f[1]))
+		b.WriteString(`"`)
+	}
+	b.WriteString("/></rdf:RDF></x:xmpmeta>\n<?xpacket end=\"w\"?>")
+	return b.Bytes()
+}
+
+// readJPEG returns the metadata in the XMP packets of a JPEG, falling back to its
+// comment, or nil if there is none.
+func readJPEG(data []byte) (*ImageMetadata, error) {
+	var xmps [][]byte
+	var com []byte
+	for pos := len(jpegSOI); pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return nil, errors.New("malformed JPEG")
+		}
+		marker := data[pos+1]
+		if marker == jpegSOS {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil, errors.New("truncated JPEG")
+		}
+		body := data[pos+4 : pos+2+length]
+		pos += 2 + length
+
+		switch {
+		case marker == jpegAPP1 && bytes.HasPrefix(body, xmpHeader):
+			xmps = append(xmps, body[len(xmpHeader):])
+		case marker == jpegCOM && com == nil && bytes.HasPrefix(body, []byte(metaKeyPrefix)):
+			com = body
+		}
+	}
+
+	var xmpErr error
+	for _, xmp := range xmps {
+		meta, err := readXMP(xmp)
+		if meta != nil {
+			return meta, nil
+		}
+		if xmpErr == nil {
+			xmpErr = err
+		}
+	}
+	if com != nil {
+		var meta ImageMetadata
+		found := false
+		for _, line := range strings.Split(string(com), "\n") {
+			kv, ok := strings.CutPrefix(line, metaKeyPrefix)
+			if !ok {
+				continue
+			}
+			if key, value, ok := strings.Cut(kv, "="); ok && meta.set(key, value) {
+				found = true
+			}
+		}
+		if found {
+			return &meta, nil
+		}
+	}
+	return nil, xmpErr
+}
+
+// readXMP returns the Leonardo properties of an XMP packet, or nil if there are none.
+func readXMP(packet []byte) (*ImageMetadata, error) {
+	var meta ImageMetadata
+	found := false
+	dec := xml.NewDecoder(bytes.NewReader(packet))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed XMP: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Description" {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Space == xmpNamespace && meta.set(attr.Name.Local, attr.Value) {
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return &meta, nil
+}
@@ -0,0 +1,143 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestImageMetadata tests embedding and reading metadata in PNG and JPEG images.
+func TestImageMetadata(t *testing.T) {
+	meta := ImageMetadata{
+		Prompt:         `A "lighthouse" at dusk, café <style> & ünïcode`,
+		NegativePrompt: "blurry",
+		Seed:           Ptr(42),
+		ModelID:        "aa77f04e-3eec-4034-9c07-d0f619684628",
+		PresetStyle:    PresetStyleCinematic,
+		GenerationID:   "gen-123",
+		ImageID:        "img-1",
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	var pngData, jpegData bytes.Buffer
+	png.Encode(&pngData, img)
+	jpeg.Encode(&jpegData, img, nil)
+
+	for name, data := range map[string][]byte{"png": pngData.Bytes(), "jpeg": jpegData.Bytes()} {
+		if _, err := ReadImageMetadata(bytes.NewReader(data)); !errors.Is(err, ErrNoImageMetadata) {
+			t.Errorf("%s: Expected ErrNoImageMetadata before embedding, got %v", name, err)
+		}
+
+		embedded, err := EmbedImageMetadata(data, meta)
+		if err != nil {
+			t.Fatalf("%s: EmbedImageMetadata failed: %v", name, err)
+		}
+		if _, _, err := image.Decode(bytes.NewReader(embedded)); err != nil {
+			t.Errorf("%s: Expected the image to still decode, got %v", name, err)
+		}
+
+		got, err := ReadImageMetadata(bytes.NewReader(embedded))
+		if err != nil {
+			t.Fatalf("%s: ReadImageMetadata failed: %v", name, err)
+		}
+		if !reflect.DeepEqual(*got, meta) {
+			t.Errorf("%s: ReadImageMetadata = %+v, want %+v", name, *got, meta)
+		}
+	}
+
+	if _, err := ReadImageMetadata(bytes.NewReader([]byte("GIF89a"))); err == nil {
+		t.Error("Expected error for unsupported format, got nil")
+	}
+}
+
+// TestImageMetadataJPEGOrder tests that metadata is inserted after the JFIF and Exif segments.
+func TestImageMetadataJPEGOrder(t *testing.T) {
+	var encoded bytes.Buffer
+	jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil)
+
+	segment := func(marker byte, body string) []byte {
+		return append([]byte{0xFF, marker, byte((len(body) + 2) >> 8), byte(len(body) + 2)}, body...)
+	}
+	data := append([]byte{}, jpegSOI...)
+	data = append(data, segment(jpegAPP0, "JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00")...)
+	data = append(data, segment(jpegAPP1, "Exif\x00\x00MM\x00*\x00\x00\x00\x08\x00\x00")...)
+	data = append(data, encoded.Bytes()[len(jpegSOI):]...)
+
+	embedded, err := EmbedImageMetadata(data, ImageMetadata{Prompt: "A lighthouse."})
+	if err != nil {
+		t.Fatalf("EmbedImageMetadata failed: %v", err)
+	}
+
+	var markers []string
+	for pos := len(jpegSOI); len(markers) < 4 && pos+4 <= len(embedded); {
+		length := int(embedded[pos+2])<<8 | int(embedded[pos+3])
+		body := embedded[pos+4 : pos+2+length]
+		switch {
+		case embedded[pos+1] == jpegAPP1 && bytes.HasPrefix(body, []byte("Exif")):
+			markers = append(markers, "exif")
+		case embedded[pos+1] == jpegAPP1 && bytes.HasPrefix(body, xmpHeader):
+			markers = append(markers, "xmp")
+		case embedded[pos+1] == jpegAPP0:
+			markers = append(markers, "jfif")
+		case embedded[pos+1] == jpegCOM:
+			markers = append(markers, "comment")
+		}
+		pos += 2 + length
+	}
+	if got := strings.Join(markers, " "); got != "jfif exif xmp comment" {
+		t.Errorf("Expected segments jfif exif xmp comment, got %s", got)
+	}
+
+	got, err := ReadImageMetadata(bytes.NewReader(embedded))
+	if err != nil || got.Prompt != "A lighthouse." {
+		t.Errorf("ReadImageMetadata = %+v, %v", got, err)
+	}
+}
+
+// TestDownloadImageWithMetadata tests embedding metadata while downloading.
+func TestDownloadImageWithMetadata(t *testing.T) {
+	var pngData bytes.Buffer
+	png.Encode(&pngData, image.NewGray(image.Rect(0, 0, 4, 4)))
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pngData.Bytes())
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	gen := &GetGenerationResponse{GenerationsByPK: GenerationDetails{ID: Ptr("gen-123"), Prompt: "A lighthouse.", Seed: Ptr(7)}}
+	var buf bytes.Buffer
+	n, err := client.Images.DownloadImage(context.Background(), server.URL+"/img-1.png", &buf, WithImageMetadata(MetadataFromGeneration(gen, "img-1")))
+	if err != nil {
+		t.Fatalf("DownloadImage failed: %v", err)
+	}
+	if n != int64(buf.Len()) || n <= int64(pngData.Len()) {
+		t.Errorf("Expected %d bytes larger than the original %d, got %d", buf.Len(), pngData.Len(), n)
+	}
+
+	meta, err := ReadImageMetadata(&buf)
+	if err != nil {
+		t.Fatalf("ReadImageMetadata failed: %v", err)
+	}
+	if meta.Prompt != "A lighthouse." || *meta.Seed != 7 || meta.GenerationID != "gen-123" || meta.ImageID != "img-1" {
+		t.Errorf("Unexpected metadata: %+v", meta)
+	}
+}
@@ -0,0 +1,406 @@
+package leonardo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements from a Client. Operations are labelled by their
+// qualified name, e.g. "Images.CreateImageGeneration". Implementations must be
+// safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called once per HTTP request with its status code
+	// (zero when no response was received), latency and error class.
+	ObserveRequest(operation string, status int, latency time.Duration, class ErrorClass)
+	// ObserveRetry is called for every request beyond the first of a call.
+	ObserveRetry(operation string)
+	// AddCredits is called with the API credit cost reported by a response.
+	AddCredits(operation string, credits int)
+	// AddJobsInFlight is called with +1 when a job is submitted and -1 when it is
+	// seen to complete or fail, or after a day if it never is. The operation is
+	// the one that submitted the job.
+	AddJobsInFlight(operation string, delta int)
+}
+
+// ErrorClass categorizes a failed request.
+type ErrorClass string
+
+const (
+	ErrorClassNone        ErrorClass = ""
+	ErrorClassCanceled    ErrorClass = "canceled"
+	ErrorClassTimeout     ErrorClass = "timeout"
+	ErrorClassTransport   ErrorClass = "transport"
+	ErrorClassRateLimited ErrorClass = "rate_limited"
+	ErrorClassClient      ErrorClass = "client"
+	ErrorClassServer      ErrorClass = "server"
+	ErrorClassDecode      ErrorClass = "decode"
+)
+
+// ClassifyError returns the ErrorClass of an error returned by the client.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassNone
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return ErrorClassRateLimited
+		case apiErr.StatusCode >= 500:
+			return ErrorClassServer
+		default:
+			return ErrorClassClient
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassCanceled
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorClassTimeout
+	}
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorClassTransport
+	}
+	return ErrorClassDecode
+}
+
+// observe reports a completed HTTP request to c.Metrics.
+func (c *Client) observe(call *Call, status int, latency time.Duration, v interface{}, err error) {
+	operation := "unknown"
+	if call != nil {
+		operation = call.Name()
+		if call.attempt > 1 {
+			c.Metrics.ObserveRetry(operation)
+		}
+	}
+	c.Metrics.ObserveRequest(operation, status, latency, ClassifyError(err))
+	if err != nil {
+		return
+	}
+
+	if credits := responseCreditCost(v); credits > 0 {
+		c.Metrics.AddCredits(operation, credits)
+	}
+	if id, status, ok := responseJobStatus(v); ok {
+		if _, submits := jobKinds[operation]; submits && status == GenerationStatusPending {
+			started, forgotten := c.jobs.start(id, operation, time.Now())
+			if started {
+				c.Metrics.AddJobsInFlight(operation, 1)
+			}
+			for _, submitted := range forgotten {
+				c.Metrics.AddJobsInFlight(submitted, -1)
+			}
+		} else if status == GenerationStatusComplete || status == GenerationStatusFailed {
+			if submitted, ok := c.jobs.finish(id); ok {
+				c.Metrics.AddJobsInFlight(submitted, -1)
+			}
+		}
+	}
+}
+
+// Limits on the jobs a jobTracker remembers. Jobs that are never seen to
+// finish, because they are polled through another client or not at all, are
+// forgotten after jobTrackerTTL, or oldest first beyond jobTrackerSize.
+const (
+	jobTrackerTTL  = 24 * time.Hour
+	jobTrackerSize = 10000
+)
+
+// jobTracker remembers which operation submitted each job still in flight.
+type jobTracker struct {
+	mu   sync.Mutex
+	jobs map[string]trackedJob
+}
+
+// trackedJob is a job in a jobTracker.
+type trackedJob struct {
+	operation string
+	started   time.Time
+}
+
+// start tracks a job submitted by operation, reporting false if it is already
+// tracked, and returns the submitting operations of the jobs forgotten to keep
+// within the limits.
+func (t *jobTracker) start(id, operation string, now time.Time) (bool, []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.jobs == nil {
+		t.jobs = map[string]trackedJob{}
+	}
+	if _, ok := t.jobs[id]; ok {
+		return false, nil
+	}
+
+	var forgotten []string
+	for len(t.jobs) >= jobTrackerSize {
+		oldest := ""
+		for jobID, job := range t.jobs {
+			if oldest == "" || job.started.Before(t.jobs[oldest].started) {
+				oldest = jobID
+			}
+		}
+		forgotten = append(forgotten, t.jobs[oldest].operation)
+		delete(t.jobs, oldest)
+	}
+	for jobID, job := range t.jobs {
+		if now.Sub(job.started) > jobTrackerTTL {
+			forgotten = append(forgotten, job.operation)
+			delete(t.jobs, jobID)
+		}
+	}
+	t.jobs[id] = trackedJob{operation: operation, started: now}
+	return true, forgotten
+}
+
+func (t *jobTracker) finish(id string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	job, ok := t.jobs[id]
+	delete(t.jobs, id)
+	return job.operation, ok
+}
+
+// responseCreditCost returns the API credit cost reported by a response, if any.
+func responseCreditCost(v interface{}) int {
+	var cost *int
+	switch resp := v.(type) {
+	case *CreateGenerationResponse:
+		cost = resp.SDGenerationJob.APICreditCost
+	case *TrainCustomModelResponse:
+		cost = resp.SDTrainingJob.APICreditCost
+	case *CreateTextureGenerationResponse:
+		cost = resp.TextureGenerationJob.APICreditCost
+	case *UpscaleVariationResponse:
+		cost = resp.SdUpscaleJob.APICreditCost
+	case *CreateUnzoomVariationResponse:
+		cost = resp.SdUnzoomJob.APICreditCost
+	case *CreateNoBackgroundVariationResponse:
+		cost = resp.SdNobgJob.APICreditCost
+	case *GenerateRandomPromptResponse:
+		if resp.PromptGeneration != nil {
+			cost = resp.PromptGeneration.APICreditCost
+		}
+	case *ImprovePromptResponse:
+		if resp.PromptGeneration != nil {
+			cost = resp.PromptGeneration.APICreditCost
+		}
+	case *CreateLCMGenerationResponse:
+		if resp.LCMGenerationJob != nil {
+			cost = resp.LCMGenerationJob.APICreditCost
+		}
+	case *PerformInstantRefineResponse:
+		if resp.LCMGenerationJob != nil {
+			cost = resp.LCMGenerationJob.APICreditCost
+		}
+	case *PerformInpaintingResponse:
+		if resp.LCMGenerationJob != nil {
+			cost = resp.LCMGenerationJob.APICreditCost
+		}
+	case *PerformAlchemyUpscaleResponse:
+		if resp.LCMGenerationJob != nil {
+			cost = resp.LCMGenerationJob.APICreditCost
+		}
+	}
+	if cost == nil {
+		return 0
+	}
+	return *cost
+}
+
+// responseJobStatus returns the ID and status of the asynchronous job a response
+// submits or reports on. Submissions are reported as PENDING; a custom model's
+// status is passed through as is, so it may be neither of the generation statuses.
+func responseJobStatus(v interface{}) (string, GenerationStatus, bool) {
+	switch resp := v.(type) {
+	case *GetGenerationResponse:
+		if resp.GenerationsByPK.ID != nil && resp.GenerationsByPK.Status != nil {
+			return *resp.GenerationsByPK.ID, *resp.GenerationsByPK.Status, true
+		}
+		return "", "", false
+	case *GetVariationResponse:
+		if len(resp.GeneratedImageVariationGeneric) > 0 {
+			v := resp.GeneratedImageVariationGeneric[0]
+			if v.ID != nil && v.Status != nil {
+				return *v.ID, *v.Status, true
+			}
+		}
+		return "", "", false
+	case *GetCustomModelResponse:
+		if resp.CustomModelsByPK.ID != nil && resp.CustomModelsByPK.Status != nil {
+			return *resp.CustomModelsByPK.ID, GenerationStatus(*resp.CustomModelsByPK.Status), true
+		}
+		return "", "", false
+	}
+	if id := responseJobID(v); id != "" {
+		return id, GenerationStatusPending, true
+	}
+	return "", "", false
+}
+
+// DefaultLatencyBuckets are the histogram buckets, in seconds, used by PrometheusMetrics.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is a dependency-free Metrics implementation that serves its
+// measurements in the Prometheus text exposition format.
+//
+//	metrics := leonardo.NewPrometheusMetrics()
+//	client.Metrics = metrics
+//	http.Handle("/metrics", metrics)
+type PrometheusMetrics struct {
+	mu       sync.Mutex
+	buckets  []float64
+	requests map[[2]string]float64 // operation, status
+	errors   map[[2]string]float64 // operation, class
+	retries  map[string]float64
+	credits  map[string]float64
+	inFlight map[string]float64
+	latency  map[string]*histogram
+}
+
+type histogram struct {
+	counts []float64
+	sum    float64
+	count  float64
+}
+
+// NewPrometheusMetrics creates a PrometheusMetrics using DefaultLatencyBuckets.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		buckets:  DefaultLatencyBuckets,
+		requests: map[[2]string]float64{},
+		errors:   map[[2]string]float64{},
+		retries:  map[string]float64{},
+		credits:  map[string]float64{},
+		inFlight: map[string]float64{},
+		latency:  map[string]*histogram{},
+	}
+}
+
+// ObserveRequest implements Metrics.
+func (m *PrometheusMetrics) ObserveRequest(operation string, status int, latency time.Duration, class ErrorClass) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[[2]string{operation, strconv.Itoa(status)}]++
+	if class != ErrorClassNone {
+		m.errors[[2]string{operation, string(class)}]++
+	}
+	h, ok := m.latency[operation]
+	if !ok {
+		h = &histogram{counts: make([]float64, len(m.buckets))}
+		m.latency[operation] = h
+	}
+	seconds := latency.Seconds()
+	for i, le := range m.buckets {
+		if seconds <= le {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// ObserveRetry implements Metrics.
+func (m *PrometheusMetrics) ObserveRetry(operation string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[operation]++
+}
+
+// AddCredits implements Metrics.
+func (m *PrometheusMetrics) AddCredits(operation string, credits int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.credits[operation] += float64(credits)
+}
+
+// AddJobsInFlight implements Metrics.
+func (m *PrometheusMetrics) AddJobsInFlight(operation string, delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight[operation] += float64(delta)
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP leonardo_requests_total Requests sent to the Leonardo.ai API.\n")
+	b.WriteString("# TYPE leonardo_requests_total counter\n")
+	for _, k := range sortedPairs(m.requests) {
+		fmt.Fprintf(&b, "leonardo_requests_total{operation=%q,status=%q} %v\n", k[0], k[1], m.requests[k])
+	}
+
+	b.WriteString("# HELP leonardo_request_errors_total Failed requests by error class.\n")
+	b.WriteString("# TYPE leonardo_request_errors_total counter\n")
+	for _, k := range sortedPairs(m.errors) {
+		fmt.Fprintf(&b, "leonardo_request_errors_total{operation=%q,class=%q} %v\n", k[0], k[1], m.errors[k])
+	}
+
+	b.WriteString("# HELP leonardo_request_duration_seconds Request latency.\n")
+	b.WriteString("# TYPE leonardo_request_duration_seconds histogram\n")
+	for _, op := range sortedKeys(m.latency) {
+		h := m.latency[op]
+		for i, le := range m.buckets {
+			fmt.Fprintf(&b, "leonardo_request_duration_seconds_bucket{operation=%q,le=%q} %v\n", op, strconv.FormatFloat(le, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(&b, "leonardo_request_duration_seconds_bucket{operation=%q,le=\"+Inf\"} %v\n", op, h.count)
+		fmt.Fprintf(&b, "leonardo_request_duration_seconds_sum{operation=%q} %v\n", op, h.sum)
+		fmt.Fprintf(&b, "leonardo_request_duration_seconds_count{operation=%q} %v\n", op, h.count)
+	}
+
+	writeFamily(&b, "leonardo_retries_total", "counter", "Requests retried beyond the first attempt.", m.retries)
+	writeFamily(&b, "leonardo_credits_spent_total", "counter", "API credits reported as spent.", m.credits)
+	writeFamily(&b, "leonardo_jobs_in_flight", "gauge", "Submitted jobs not yet seen to complete.", m.inFlight)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeFamily(b *strings.Builder, name, typ, help string, values map[string]float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, op := range sortedKeys(values) {
+		fmt.Fprintf(b, "%s{operation=%q} %v\n", name, op, values[op])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]float64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
@@ -0,0 +1,192 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPrometheusMetrics tests request, error, credit and in-flight job metrics.
+func TestPrometheusMetrics(t *testing.T) {
+	status := "PENDING"
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/generations" && r.Method == "POST":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sdGenerationJob": map[string]interface{}{"generationId": "gen-123", "apiCreditCost": 8},
+			})
+		case r.URL.Path == "/generations/gen-123":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"generations_by_pk": map[string]interface{}{"id": "gen-123", "status": status},
+			})
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(APIErrorResponse{Code: "rate-limited", Message: "Too many requests."})
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	metrics := NewPrometheusMetrics()
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+		Metrics:    metrics,
+	}
+	client.Images = client.NewImagesService()
+	client.User = client.NewUserService()
+
+	ctx := context.Background()
+	if _, err := client.Images.CreateImageGeneration(ctx, CreateGenerationRequest{Prompt: "A foggy forest."}); err != nil {
+		t.Fatalf("CreateImageGeneration failed: %v", err)
+	}
+	if _, err := client.Images.GetImageGeneration(ctx, "gen-123"); err != nil {
+		t.Fatalf("GetImageGeneration failed: %v", err)
+	}
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		return rec.Body.String()
+	}
+
+	out := scrape()
+	for _, want := range []string{
+		`leonardo_requests_total{operation="Images.CreateImageGeneration",status="200"} 1`,
+		`leonardo_credits_spent_total{operation="Images.CreateImageGeneration"} 8`,
+		`leonardo_jobs_in_flight{operation="Images.CreateImageGeneration"} 1`,
+		`leonardo_request_duration_seconds_count{operation="Images.GetImageGeneration"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `leonardo_jobs_in_flight{operation="Images.GetImageGeneration"}`) {
+		t.Errorf("Expected a pending poll not to be tracked as a submission, got:\n%s", out)
+	}
+
+	status = "COMPLETE"
+	if _, err := client.Images.GetImageGeneration(ctx, "gen-123"); err != nil {
+		t.Fatalf("GetImageGeneration failed: %v", err)
+	}
+	if _, err := client.User.GetUserInfo(ctx); err == nil {
+		t.Fatal("Expected rate limit error, got nil")
+	}
+
+	out = scrape()
+	for _, want := range []string{
+		`leonardo_jobs_in_flight{operation="Images.CreateImageGeneration"} 0`,
+		`leonardo_requests_total{operation="User.GetUserInfo",status="429"} 1`,
+		`leonardo_request_errors_total{operation="User.GetUserInfo",class="rate_limited"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestClassifyError tests the error classes reported to Metrics.
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorClass
+	}{
+		{nil, ErrorClassNone},
+		{&APIError{StatusCode: 429}, ErrorClassRateLimited},
+		{&APIError{StatusCode: 503}, ErrorClassServer},
+		{&APIError{StatusCode: 404}, ErrorClassClient},
+		{context.Canceled, ErrorClassCanceled},
+		{context.DeadlineExceeded, ErrorClassTimeout},
+	}
+	for _, tt := range tests {
+		if got := ClassifyError(tt.err); got != tt.want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestJobTrackerExpiry tests that jobs never seen to finish are forgotten.
+func TestJobTrackerExpiry(t *testing.T) {
+	var jobs jobTracker
+	now := time.Now()
+	jobs.start("gen-1", "Images.CreateImageGeneration", now.Add(-jobTrackerTTL-time.Minute))
+	started, forgotten := jobs.start("gen-2", "Motion.CreateSVDMotionGeneration", now)
+	if !started || len(forgotten) != 1 || forgotten[0] != "Images.CreateImageGeneration" {
+		t.Errorf("Expected gen-1 to expire, got %v %v", started, forgotten)
+	}
+	if _, ok := jobs.finish("gen-1"); ok {
+		t.Error("Expected gen-1 to be forgotten")
+	}
+	if operation, ok := jobs.finish("gen-2"); !ok || operation != "Motion.CreateSVDMotionGeneration" {
+		t.Errorf("Expected gen-2 to be tracked, got %q", operation)
+	}
+}
+
+// TestMetricsTrainingInFlight tests that a training model stays in flight until it completes.
+func TestMetricsTrainingInFlight(t *testing.T) {
+	status := "TRAINING"
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/models" && r.Method == "POST":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sdTrainingJob": map[string]interface{}{"customModelId": "model-123", "apiCreditCost": 200},
+			})
+		case r.URL.Path == "/models/model-123":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"custom_models_by_pk": map[string]interface{}{"id": "model-123", "status": status},
+			})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	metrics := NewPrometheusMetrics()
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+		Metrics:    metrics,
+	}
+	client.Models = client.NewModelsService()
+
+	ctx := context.Background()
+	if _, err := client.Models.TrainCustomModel(ctx, TrainCustomModelRequest{Name: "Landscape Model", DatasetID: "dataset-123"}); err != nil {
+		t.Fatalf("TrainCustomModel failed: %v", err)
+	}
+	inFlight := func(want string) {
+		t.Helper()
+		var buf strings.Builder
+		metrics.WriteTo(&buf)
+		if line := `leonardo_jobs_in_flight{operation="Models.TrainCustomModel"} ` + want; !strings.Contains(buf.String(), line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, buf.String())
+		}
+	}
+
+	if _, err := client.Models.GetCustomModel(ctx, "model-123"); err != nil {
+		t.Fatalf("GetCustomModel failed: %v", err)
+	}
+	inFlight("1")
+
+	status = "COMPLETE"
+	if _, err := client.Models.GetCustomModel(ctx, "model-123"); err != nil {
+		t.Fatalf("GetCustomModel failed: %v", err)
+	}
+	inFlight("0")
+}
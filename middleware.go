@@ -0,0 +1,89 @@
+package leonardo
+
+import (
+	"context"
+	"fmt"
+)
+
+// Call describes a single logical API call as it passes through the middleware chain.
+type Call struct {
+	Service   string // Client service field, e.g. "Images"
+	Operation string // service method, e.g. "CreateImageGeneration"
+	Method    string
+	Path      string
+	ID        string      // ID of the resource addressed by the call, if any
+	Request   interface{} // request struct sent as the JSON body, or nil
+	Response  interface{} // pointer to the response struct the result is decoded into
+
+	attempt     int
+	intercepted bool // skipped the network under Client.DryRun
+}
+
+// Name returns the qualified operation name, e.g. "Images.CreateImageGeneration".
+func (c *Call) Name() string {
+	return c.Service + "." + c.Operation
+}
+
+// Attempt returns the number of HTTP requests sent for the call so far.
+// It exceeds one when middleware retries the call.
+func (c *Call) Attempt() int {
+	return c.attempt
+}
+
+// Handler performs a Call.
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps a Handler with cross-cutting behavior such as logging,
+// metrics, caching or budgeting. Middleware may inspect call.Request before
+// invoking next and call.Response after it returns.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the client's chain. The first middleware added is the outermost.
+func (c *Client) Use(mw ...Middleware) {
+	c.Middleware = append(c.Middleware, mw...)
+}
+
+type callContextKey struct{}
+
+// CallFromContext returns the Call in progress for ctx, if any.
+// It is available to middleware, Client.Do and the HTTP transport.
+func CallFromContext(ctx context.Context) (*Call, bool) {
+	call, ok := ctx.Value(callContextKey{}).(*Call)
+	return call, ok
+}
+
+// invoke runs the call through the middleware chain and sends it, within a span
+// named after the call.
+func (c *Client) invoke(ctx context.Context, call *Call) (err error) {
+	ctx, span := c.startSpan(ctx, call.Name(),
+		Attr("leonardo.service", call.Service),
+		Attr("leonardo.operation", call.Operation),
+		Attr("http.method", call.Method),
+		Attr("leonardo.path", call.Path),
+	)
+	if call.ID != "" {
+		span.SetAttributes(Attr("leonardo.id", call.ID))
+	}
+	defer func() {
+		span.SetAttributes(Attr("leonardo.attempts", call.attempt))
+		if id := responseJobID(call.Response); err == nil && id != "" {
+			span.SetAttributes(Attr("leonardo.job_id", id))
+		}
+		endSpan(span, err)
+	}()
+
+	h := Handler(c.send)
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		h = c.Middleware[i](h)
+	}
+	return h(context.WithValue(ctx, callContextKey{}, call), call)
+}
+
+// send is the innermost Handler: it builds the HTTP request and decodes the response.
+func (c *Client) send(ctx context.Context, call *Call) error {
+	httpReq, err := c.NewRequest(ctx, call.Method, call.Path, call.Request)
+	if err != nil {
+		return fmt.Errorf("creating %s request failed: %w", call.Operation, err)
+	}
+	return c.Do(httpReq, call.Response)
+}
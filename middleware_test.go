@@ -0,0 +1,114 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMiddlewareChain tests that middleware wraps each call in order with the call details available.
+func TestMiddlewareChain(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generations" || r.Method != "POST" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sdGenerationJob": map[string]interface{}{"generationId": "gen-123", "apiCreditCost": 8},
+		})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) error {
+				order = append(order, name+">"+call.Name())
+				if c, ok := CallFromContext(ctx); !ok || c != call {
+					t.Errorf("Expected the call to be available from the context")
+				}
+				err := next(ctx, call)
+				order = append(order, name+"<")
+				return err
+			}
+		}
+	}
+
+	var prompt, generationID string
+	client.Use(trace("outer"), trace("inner"), func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if req, ok := call.Request.(CreateGenerationRequest); ok {
+				prompt = req.Prompt
+			}
+			err := next(ctx, call)
+			if resp, ok := call.Response.(*CreateGenerationResponse); ok && resp.SDGenerationJob.GenerationID != nil {
+				generationID = *resp.SDGenerationJob.GenerationID
+			}
+			return err
+		}
+	})
+
+	_, err := client.Images.CreateImageGeneration(context.Background(), CreateGenerationRequest{Prompt: "A quiet harbor."})
+	if err != nil {
+		t.Fatalf("CreateImageGeneration failed: %v", err)
+	}
+
+	expected := []string{"outer>Images.CreateImageGeneration", "inner>Images.CreateImageGeneration", "inner<", "outer<"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, order)
+			break
+		}
+	}
+	if prompt != "A quiet harbor." {
+		t.Errorf("Expected middleware to see the request prompt, got %q", prompt)
+	}
+	if generationID != "gen-123" {
+		t.Errorf("Expected middleware to see the response generation ID, got %q", generationID)
+	}
+}
+
+// TestMiddlewareShortCircuit tests that middleware can answer a call without reaching the API.
+func TestMiddlewareShortCircuit(t *testing.T) {
+	client := &Client{
+		BaseURL:    "http://127.0.0.1:0",
+		HTTPClient: http.DefaultClient,
+		APIKey:     "test-api-key",
+	}
+	client.Models = client.NewModelsService()
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if call.Name() != "Models.GetCustomModel" || call.ID != "model-123" {
+				return next(ctx, call)
+			}
+			resp := call.Response.(*GetCustomModelResponse)
+			resp.CustomModelsByPK.ID = Ptr(call.ID)
+			resp.CustomModelsByPK.Name = Ptr("Cached Model")
+			return nil
+		}
+	})
+
+	resp, err := client.Models.GetCustomModel(context.Background(), "model-123")
+	if err != nil {
+		t.Fatalf("GetCustomModel failed: %v", err)
+	}
+	if resp.CustomModelsByPK.Name == nil || *resp.CustomModelsByPK.Name != "Cached Model" {
+		t.Errorf("Expected the short-circuited response, got %v", resp.CustomModelsByPK.Name)
+	}
+}
@@ -22,12 +22,14 @@ func (s *ModelsService) TrainCustomModel(ctx context.Context, req TrainCustomMod
 	var resp TrainCustomModelResponse
 	path := "/models"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating TrainCustomModel request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Models",
+		Operation: "TrainCustomModel",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("training custom model failed: %w", err)
 	}
@@ -41,12 +43,14 @@ func (s *ModelsService) GetCustomModel(ctx context.Context, id string) (*GetCust
 	var resp GetCustomModelResponse
 	path := fmt.Sprintf("/models/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating GetCustomModel request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Models",
+		Operation: "GetCustomModel",
+		Method:    http.MethodGet,
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving custom model failed: %w", err)
 	}
@@ -60,12 +64,14 @@ func (s *ModelsService) DeleteCustomModel(ctx context.Context, id string) (*Dele
 	var resp DeleteCustomModelResponse
 	path := fmt.Sprintf("/models/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating DeleteCustomModel request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Models",
+		Operation: "DeleteCustomModel",
+		Method:    http.MethodDelete,
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("deleting custom model failed: %w", err)
 	}
@@ -79,12 +85,13 @@ func (s *ModelsService) ListPlatformModels(ctx context.Context, req PaginationPa
 	var resp ListPlatformModelsResponse
 	path := fmt.Sprintf("/platformModels?limit=%d&offset=%d", req.Limit, req.Offset)
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating ListPlatformModels request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Models",
+		Operation: "ListPlatformModels",
+		Method:    "GET",
+		Path:      path,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("listing platform models failed: %w", err)
 	}
@@ -98,12 +105,15 @@ func (s *ModelsService) UpdateCustomModel(ctx context.Context, id string, req Up
 	var resp UpdateCustomModelResponse
 	path := fmt.Sprintf("/models/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, "PUT", path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating UpdateCustomModel request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Models",
+		Operation: "UpdateCustomModel",
+		Method:    "PUT",
+		Path:      path,
+		ID:        id,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("updating custom model failed: %w", err)
 	}
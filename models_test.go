@@ -21,10 +21,7 @@ func TestTrainCustomModel(t *testing.T) {
 		}
 		w.Header().Set("Content-Type", "application/json")
 		response := TrainCustomModelResponse{
-			SDTrainingJob: struct {
-				APICreditCost *int    `json:"apiCreditCost"`
-				CustomModelID *string `json:"customModelId"`
-			}{
+			SDTrainingJob: TrainingJob{
 				APICreditCost: Ptr(100),
 				CustomModelID: Ptr("model-123"),
 			},
@@ -78,21 +75,8 @@ func TestGetCustomModel(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := GetCustomModelResponse{
-			CustomModelsByPK: struct {
-				CreatedAt      *Time   `json:"createdAt"`
-				Description    *string `json:"description"`
-				ID             *string `json:"id"`
-				InstancePrompt *string `json:"instancePrompt"`
-				ModelHeight    *int    `json:"modelHeight"`
-				ModelWidth     *int    `json:"modelWidth"`
-				Name           *string `json:"name"`
-				Public         *bool   `json:"public"`
-				SDVersion      *string `json:"sdVersion"`
-				Status         *string `json:"status"`
-				Type           *string `json:"type"`
-				UpdatedAt      *Time   `json:"updatedAt"`
-			}{
-				CreatedAt:      &Time{time.Now().Add(-48 * time.Hour)},
+			CustomModelsByPK: CustomModel{
+				CreatedAt:      &Time{Time: time.Now().Add(-48 * time.Hour)},
 				Description:    Ptr("A custom model for landscape generation."),
 				ID:             Ptr("model-123"),
 				InstancePrompt: Ptr("Detailed description for model instances."),
@@ -103,7 +87,7 @@ func TestGetCustomModel(t *testing.T) {
 				SDVersion:      Ptr("v1.5"),
 				Status:         Ptr("TRAINING"),
 				Type:           Ptr("GENERAL"),
-				UpdatedAt:      &Time{time.Now().Add(-24 * time.Hour)},
+				UpdatedAt:      &Time{Time: time.Now().Add(-24 * time.Hour)},
 			},
 		}
 		json.NewEncoder(w).Encode(response)
@@ -149,9 +133,7 @@ func TestDeleteCustomModel(t *testing.T) {
 		// Mock response
 		w.Header().Set("Content-Type", "application/json")
 		response := DeleteCustomModelResponse{
-			DeleteCustomModelsByPK: struct {
-				ID *string `json:"id"`
-			}{
+			DeleteCustomModelsByPK: ResourceID{
 				ID: Ptr("model-123"),
 			},
 		}
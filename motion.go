@@ -21,12 +21,14 @@ func (s *MotionService) CreateSVDMotionGeneration(ctx context.Context, req Motio
 	var resp CreateSVDMotionGenerationResponse
 	path := "/generations-motion-svd"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateSVDMotionGeneration request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Motion",
+		Operation: "CreateSVDMotionGeneration",
+		Method:    "POST",
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating SVD motion generation failed: %w", err)
 	}
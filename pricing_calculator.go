@@ -3,6 +3,7 @@ package leonardo
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // PricingCalculatorService provides methods to interact with the Pricing Calculator endpoints.
@@ -21,15 +22,133 @@ func (s *PricingCalculatorService) CalculateAPICost(ctx context.Context, req Cal
 	var resp CalculateAPICostResponse
 	path := "/pricing-calculator"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, req)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "PricingCalculator",
+		Operation: "CalculateAPICost",
+		Method:    "POST",
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("creating CalculateAPICost request failed: %w", err)
+		return nil, fmt.Errorf("calculating API cost failed: %w", err)
 	}
 
-	err = s.client.Do(httpReq, &resp)
+	return &resp, nil
+}
+
+// EstimateGeneration calculates the API credit cost of the given generation request
+// without submitting it. A model not in KnownModels is looked up in Client.Catalog
+// and, failing that, with GetCustomModel, so custom models are priced as such.
+// ControlNet costs are not included; to price them, set ControlnetsCost on the
+// params from NewImageGenerationPricingParams and call CalculateAPICost.
+// POST /pricing-calculator
+func (s *PricingCalculatorService) EstimateGeneration(ctx context.Context, req CreateGenerationRequest) (*CalculateAPICostResponse, error) {
+	params, err := s.generationParams(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("calculating API cost failed: %w", err)
+		return nil, fmt.Errorf("estimating generation failed: %w", err)
 	}
+	return s.CalculateAPICost(ctx, CalculateAPICostRequest{
+		Service: PricingServiceTypeImageGeneration,
+		ServiceParams: &PricingServiceParams{
+			ImageGeneration: params,
+		},
+	})
+}
 
-	return &resp, nil
+// generationParams derives the pricing params of req, looking up a model not in
+// KnownModels.
+func (s *PricingCalculatorService) generationParams(ctx context.Context, req CreateGenerationRequest) (*ImageGenerationPricingParams, error) {
+	params := NewImageGenerationPricingParams(req)
+	id := deref(req.ModelID)
+	if id == "" {
+		return params, nil
+	}
+	if _, ok := ModelByID(id); ok {
+		return params, nil
+	}
+
+	if s.client.Catalog != nil {
+		if model, err := s.client.Catalog.ModelByID(ctx, id); err == nil {
+			setPricingModel(params, model.SDVersion, false)
+			return params, nil
+		}
+	}
+	models := s.client.Models
+	if models == nil {
+		models = s.client.NewModelsService()
+	}
+	resp, err := models.GetCustomModel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("looking up model %s failed: %w", id, err)
+	}
+	setPricingModel(params, SDVersion(deref(resp.CustomModelsByPK.SDVersion)), true)
+	return params, nil
+}
+
+// NewImageGenerationPricingParams derives the image generation pricing parameters from a
+// generation request, applying the API defaults for any fields left unset. The
+// model is priced from KnownModels, or from SDVersion if it isn't listed there;
+// use EstimateGeneration to look up other models. Elements are counted as LoRAs.
+// ControlnetsCost is left unset.
+func NewImageGenerationPricingParams(req CreateGenerationRequest) *ImageGenerationPricingParams {
+	params := &ImageGenerationPricingParams{
+		AlchemyMode:         Ptr(true),
+		HighResolution:      Ptr(false),
+		ImageHeight:         Ptr(DefaultHeight),
+		ImageWidth:          Ptr(DefaultWidth),
+		InferenceSteps:      Ptr(15),
+		IsModelCustom:       Ptr(false),
+		IsPhoenix:           Ptr(false),
+		IsSDXL:              Ptr(false),
+		NumImages:           Ptr(4),
+		PromptMagic:         Ptr(false),
+		PromptMagicStrength: req.PromptMagicStrength,
+		PromptMagicVersion:  req.PromptMagicVersion,
+		Ultra:               req.Ultra,
+	}
+
+	if req.Alchemy != nil {
+		params.AlchemyMode = Ptr(*req.Alchemy)
+	}
+	if req.HighResolution != nil {
+		params.HighResolution = Ptr(*req.HighResolution)
+	}
+	if req.Height != nil {
+		params.ImageHeight = Ptr(*req.Height)
+	}
+	if req.Width != nil {
+		params.ImageWidth = Ptr(*req.Width)
+	}
+	if req.NumInferenceSteps != nil {
+		params.InferenceSteps = Ptr(*req.NumInferenceSteps)
+	}
+	if req.NumImages != nil {
+		params.NumImages = Ptr(*req.NumImages)
+	}
+	if req.PromptMagic != nil {
+		params.PromptMagic = Ptr(*req.PromptMagic)
+	}
+	if len(req.Elements) > 0 {
+		params.LoraCount = Ptr(len(req.Elements))
+	}
+
+	id := deref(req.ModelID)
+	if id == "" && req.SDVersion == nil {
+		id = DefaultModelID
+	}
+	if model, ok := ModelByID(id); ok {
+		setPricingModel(params, model.SDVersion, false)
+	} else if req.SDVersion != nil {
+		setPricingModel(params, *req.SDVersion, false)
+	}
+
+	return params
+}
+
+// setPricingModel sets the model fields of params from the model's base version.
+func setPricingModel(params *ImageGenerationPricingParams, sdVersion SDVersion, custom bool) {
+	params.IsModelCustom = Ptr(custom)
+	params.IsSDXL = Ptr(strings.HasPrefix(string(sdVersion), "SDXL"))
+	params.IsPhoenix = Ptr(sdVersion == SDVersionPhoenix)
 }
@@ -38,9 +38,7 @@ func TestCalculateAPICostSuccess(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := CalculateAPICostResponse{
-			CalculateProductionApiServiceCost: struct {
-				Cost *int `json:"cost"`
-			}{
+			CalculateProductionApiServiceCost: ServiceCost{
 				Cost: Ptr(20),
 			},
 		}
@@ -61,10 +59,12 @@ func TestCalculateAPICostSuccess(t *testing.T) {
 	// Execute CalculateAPICost
 	ctx := context.Background()
 	req := CalculateAPICostRequest{
-		Service: "IMAGE_GENERATION",
-		ServiceParams: map[string]interface{}{
-			"param1": "value1",
-			"param2": 123,
+		Service: PricingServiceTypeImageGeneration,
+		ServiceParams: &PricingServiceParams{
+			ImageGeneration: &ImageGenerationPricingParams{
+				ImageWidth: Ptr(512),
+				NumImages:  Ptr(2),
+			},
 		},
 	}
 
@@ -134,3 +134,140 @@ func TestCalculateAPICostBadRequest(t *testing.T) {
 		t.Errorf("Expected error message '%s', got '%s'", expectedErrMsg, err.Error())
 	}
 }
+
+// TestEstimateGeneration tests that EstimateGeneration derives the pricing params from a generation request.
+func TestEstimateGeneration(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/pricing-calculator"
+		if r.URL.Path != expectedPath || r.Method != "POST" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		// Decode request body
+		var req CalculateAPICostRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request body: %v", err)
+		}
+
+		// Validate derived params
+		if req.Service != PricingServiceTypeImageGeneration {
+			t.Errorf("Expected service %s, got %s", PricingServiceTypeImageGeneration, req.Service)
+		}
+		if req.ServiceParams == nil || req.ServiceParams.ImageGeneration == nil {
+			t.Fatal("Expected IMAGE_GENERATION service params, got nil")
+		}
+		params := req.ServiceParams.ImageGeneration
+		if params.ImageWidth == nil || *params.ImageWidth != 512 {
+			t.Errorf("Expected imageWidth 512, got %v", params.ImageWidth)
+		}
+		if params.ImageHeight == nil || *params.ImageHeight != 768 {
+			t.Errorf("Expected default imageHeight 768, got %v", params.ImageHeight)
+		}
+		if params.NumImages == nil || *params.NumImages != 4 {
+			t.Errorf("Expected default numImages 4, got %v", params.NumImages)
+		}
+		if params.AlchemyMode == nil || *params.AlchemyMode {
+			t.Errorf("Expected alchemyMode false, got %v", params.AlchemyMode)
+		}
+		if params.IsSDXL == nil || !*params.IsSDXL {
+			t.Errorf("Expected isSDXL true, got %v", params.IsSDXL)
+		}
+
+		// Mock successful response
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CalculateAPICostResponse{
+			CalculateProductionApiServiceCost: ServiceCost{
+				Cost: Ptr(16),
+			},
+		})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.PricingCalculator = client.NewPricingCalculatorService()
+
+	// Execute EstimateGeneration
+	resp, err := client.PricingCalculator.EstimateGeneration(context.Background(), CreateGenerationRequest{
+		Prompt:    "A lighthouse at dusk.",
+		Alchemy:   Ptr(false),
+		Width:     Ptr(512),
+		SDVersion: Ptr(SDVersionSDXL_1_0),
+	})
+	if err != nil {
+		t.Fatalf("EstimateGeneration failed: %v", err)
+	}
+
+	if resp.CalculateProductionApiServiceCost.Cost == nil || *resp.CalculateProductionApiServiceCost.Cost != 16 {
+		t.Errorf("Expected Cost 16, got %v", resp.CalculateProductionApiServiceCost.Cost)
+	}
+}
+
+// TestEstimateGenerationModel tests that EstimateGeneration prices the model and elements of a request.
+func TestEstimateGenerationModel(t *testing.T) {
+	var params *ImageGenerationPricingParams
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/models/model-123" && r.Method == "GET":
+			json.NewEncoder(w).Encode(GetCustomModelResponse{CustomModelsByPK: CustomModel{
+				ID:        Ptr("model-123"),
+				SDVersion: Ptr(string(SDVersionSDXL_1_0)),
+			}})
+		case r.URL.Path == "/pricing-calculator" && r.Method == "POST":
+			var req CalculateAPICostRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Error decoding request body: %v", err)
+			}
+			params = req.ServiceParams.ImageGeneration
+			json.NewEncoder(w).Encode(CalculateAPICostResponse{CalculateProductionApiServiceCost: ServiceCost{Cost: Ptr(24)}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.PricingCalculator = client.NewPricingCalculatorService()
+	client.Models = client.NewModelsService()
+
+	ctx := context.Background()
+	if _, err := client.PricingCalculator.EstimateGeneration(ctx, CreateGenerationRequest{
+		Prompt:   "A lighthouse at dusk.",
+		ModelID:  Ptr("model-123"),
+		Elements: []ElementWeight{{AKUUID: "element-1", Weight: 0.5}, {AKUUID: "element-2", Weight: 0.8}},
+	}); err != nil {
+		t.Fatalf("EstimateGeneration failed: %v", err)
+	}
+	if !deref(params.IsModelCustom) || !deref(params.IsSDXL) || deref(params.IsPhoenix) || deref(params.LoraCount) != 2 {
+		t.Errorf("Unexpected params for a custom SDXL model: %+v", params)
+	}
+
+	phoenix, _ := ModelByName("Phoenix")
+	if _, err := client.PricingCalculator.EstimateGeneration(ctx, CreateGenerationRequest{
+		Prompt:  "A lighthouse at dusk.",
+		ModelID: Ptr(phoenix.ID),
+	}); err != nil {
+		t.Fatalf("EstimateGeneration failed: %v", err)
+	}
+	if deref(params.IsModelCustom) || deref(params.IsSDXL) || !deref(params.IsPhoenix) || params.LoraCount != nil {
+		t.Errorf("Unexpected params for Phoenix: %+v", params)
+	}
+}
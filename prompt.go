@@ -22,12 +22,13 @@ func (s *PromptService) GenerateRandomPrompt(ctx context.Context) (*GenerateRand
 	var resp GenerateRandomPromptResponse
 	path := "/prompt/random"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating GenerateRandomPrompt request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Prompt",
+		Operation: "GenerateRandomPrompt",
+		Method:    "POST",
+		Path:      path,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("generating random prompt failed: %w", err)
 	}
@@ -41,12 +42,14 @@ func (s *PromptService) ImprovePrompt(ctx context.Context, req ImprovePromptRequ
 	var resp ImprovePromptResponse
 	path := "/prompt/improve"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating ImprovePrompt request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Prompt",
+		Operation: "ImprovePrompt",
+		Method:    "POST",
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("improving prompt failed: %w", err)
 	}
@@ -20,10 +20,7 @@ func TestGenerateRandomPrompt(t *testing.T) {
 		// Mock successful generation response
 		w.Header().Set("Content-Type", "application/json")
 		response := GenerateRandomPromptResponse{
-			PromptGeneration: &struct {
-				APICreditCost *int    `json:"apiCreditCost"`
-				Prompt        *string `json:"prompt"`
-			}{
+			PromptGeneration: &PromptGeneration{
 				APICreditCost: Ptr(4),
 				Prompt:        Ptr("A vibrant sunset over a tranquil lake."),
 			},
@@ -84,10 +81,7 @@ func TestImprovePrompt(t *testing.T) {
 		// Mock successful improvement response
 		w.Header().Set("Content-Type", "application/json")
 		response := ImprovePromptResponse{
-			PromptGeneration: &struct {
-				APICreditCost *int    `json:"apiCreditCost"`
-				Prompt        *string `json:"prompt"`
-			}{
+			PromptGeneration: &PromptGeneration{
 				APICreditCost: Ptr(5),
 				Prompt:        Ptr("A tranquil mountain landscape at sunrise, with soft light and mist."),
 			},
@@ -0,0 +1,225 @@
+package leonardo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OperationGroup classifies calls that share a rate limit.
+type OperationGroup string
+
+const (
+	// GroupGeneration is every call that submits a paid job.
+	GroupGeneration OperationGroup = "generation"
+	// GroupPolling is every GET.
+	GroupPolling OperationGroup = "polling"
+	// GroupUpload is every call that uploads an image or model.
+	GroupUpload OperationGroup = "upload"
+	// GroupPrompt is the prompt tools.
+	GroupPrompt OperationGroup = "prompt"
+	// GroupOther is everything else, such as deletes.
+	GroupOther OperationGroup = "other"
+)
+
+// generationServices are the services whose POSTs submit jobs.
+var generationServices = map[string]bool{
+	"Images":         true,
+	"Motion":         true,
+	"RealtimeCanvas": true,
+	"Texture":        true,
+	"Variation":      true,
+}
+
+// GroupOf returns the OperationGroup of a call.
+func GroupOf(call *Call) OperationGroup {
+	switch {
+	case call.Service == "Prompt":
+		return GroupPrompt
+	case strings.HasPrefix(call.Operation, "Upload"):
+		return GroupUpload
+	case call.Method == http.MethodGet:
+		return GroupPolling
+	case call.Method == http.MethodPost && (generationServices[call.Service] || call.Name() == "Models.TrainCustomModel"):
+		return GroupGeneration
+	}
+	return GroupOther
+}
+
+// requestGroup returns the OperationGroup of an HTTP request sent by the client.
+func requestGroup(req *http.Request) OperationGroup {
+	if call, ok := CallFromContext(req.Context()); ok {
+		return GroupOf(call)
+	}
+	if req.Method == http.MethodGet {
+		return GroupPolling
+	}
+	return GroupOther
+}
+
+// RateLimit is the sustained rate, in requests per second, and the burst
+// allowed for an OperationGroup.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter holds requests back so that each OperationGroup stays within
+// its RateLimit. When set on Client.RateLimiter, every request waits for a
+// token, respecting its context, instead of failing. The limiter slows a
+// group down when the API answers 429 or its rate limit headers report no
+// requests remaining, and speeds it back up to the configured rate as
+// requests succeed. It is safe for concurrent use.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  map[OperationGroup]RateLimit
+	buckets map[OperationGroup]*tokenBucket
+}
+
+// tokenBucket is the state of one OperationGroup.
+type tokenBucket struct {
+	limit  RateLimit
+	rate   float64 // current rate, lowered after 429s
+	tokens float64
+	last   time.Time
+	until  time.Time // no requests before this time
+}
+
+// NewRateLimiter creates a RateLimiter. Groups missing from limits are not limited.
+func NewRateLimiter(limits map[OperationGroup]RateLimit) *RateLimiter {
+	l := &RateLimiter{limits: map[OperationGroup]RateLimit{}, buckets: map[OperationGroup]*tokenBucket{}}
+	for group, limit := range limits {
+		l.SetLimit(group, limit)
+	}
+	return l
+}
+
+// SetLimit sets the limit of group.
+func (l *RateLimiter) SetLimit(group OperationGroup, limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	l.limits[group] = limit
+	l.buckets[group] = &tokenBucket{limit: limit, rate: limit.Rate, tokens: float64(limit.Burst), last: time.Now()}
+}
+
+// Rate returns the current rate of group, which is below its limit while the
+// limiter is backing off.
+func (l *RateLimiter) Rate(group OperationGroup) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[group]; ok {
+		return b.rate
+	}
+	return 0
+}
+
+// Wait blocks until a request in group may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, group OperationGroup) error {
+	for {
+		delay, ok := l.reserve(group)
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token from group's bucket, or returns how long to wait for one.
+func (l *RateLimiter) reserve(group OperationGroup) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[group]
+	if !ok {
+		return 0, true
+	}
+
+	now := time.Now()
+	b.tokens = min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if now.Before(b.until) {
+		return b.until.Sub(now), false
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	if b.rate <= 0 {
+		return time.Second, false
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second)), false
+}
+
+// observe adapts group's bucket to a response.
+func (l *RateLimiter) observe(group OperationGroup, resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[group]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	wait := retryAfter(resp.Header, now)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// Halve the rate, down to a request a minute, and drain the bucket.
+		b.rate = max(b.rate/2, 1.0/60)
+		b.tokens = 0
+		if wait == 0 {
+			wait = time.Duration(float64(time.Second) / b.rate)
+		}
+	} else if b.rate < b.limit.Rate {
+		// Recover a twentieth of the limit per successful request.
+		b.rate = min(b.limit.Rate, b.rate+b.limit.Rate/20)
+	}
+	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+		if n, err := strconv.Atoi(remaining); err == nil {
+			b.tokens = min(b.tokens, float64(n))
+			if n == 0 && wait == 0 {
+				wait = rateLimitReset(resp.Header, now)
+			}
+		}
+	}
+	if until := now.Add(wait); wait > 0 && until.After(b.until) {
+		b.until = until
+	}
+}
+
+// retryAfter returns the delay requested by a Retry-After header, or zero.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// rateLimitReset returns the delay until an X-RateLimit-Reset header, given
+// either in seconds from now or as a Unix time, or zero.
+func rateLimitReset(h http.Header, now time.Time) time.Duration {
+	v, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil || v <= 0 {
+		return 0
+	}
+	if reset := time.Unix(v, 0); reset.After(now) {
+		return reset.Sub(now)
+	}
+	return time.Duration(v) * time.Second
+}
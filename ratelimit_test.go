@@ -0,0 +1,109 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGroupOf tests classifying calls into operation groups.
+func TestGroupOf(t *testing.T) {
+	tests := []struct {
+		call Call
+		want OperationGroup
+	}{
+		{Call{Service: "Images", Operation: "CreateImageGeneration", Method: "POST"}, GroupGeneration},
+		{Call{Service: "Models", Operation: "TrainCustomModel", Method: "POST"}, GroupGeneration},
+		{Call{Service: "Images", Operation: "GetImageGeneration", Method: "GET"}, GroupPolling},
+		{Call{Service: "InitImages", Operation: "UploadInitImage", Method: "POST"}, GroupUpload},
+		{Call{Service: "Prompt", Operation: "ImprovePrompt", Method: "POST"}, GroupPrompt},
+		{Call{Service: "Images", Operation: "DeleteGeneration", Method: "DELETE"}, GroupOther},
+		{Call{Service: "Datasets", Operation: "CreateDataset", Method: "POST"}, GroupOther},
+	}
+	for _, tt := range tests {
+		if got := GroupOf(&tt.call); got != tt.want {
+			t.Errorf("GroupOf(%s) = %s, want %s", tt.call.Name(), got, tt.want)
+		}
+	}
+}
+
+// TestRateLimiter tests pacing requests and backing off after 429s.
+func TestRateLimiter(t *testing.T) {
+	limited := false
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limited {
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(APIErrorResponse{Message: "Too many requests."})
+			return
+		}
+		json.NewEncoder(w).Encode(ListElementsResponse{})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Elements = client.NewElementsService()
+	client.RateLimiter = NewRateLimiter(map[OperationGroup]RateLimit{GroupPolling: {Rate: 50, Burst: 2}})
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.Elements.ListElements(ctx); err != nil {
+			t.Fatalf("ListElements failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("Expected the requests beyond the burst to be paced, took %v", elapsed)
+	}
+
+	limited = true
+	if _, err := client.Elements.ListElements(ctx); err == nil {
+		t.Fatal("Expected the 429 to be returned")
+	}
+	if rate := client.RateLimiter.Rate(GroupPolling); rate != 25 {
+		t.Errorf("Expected the rate to be halved to 25, got %v", rate)
+	}
+
+	// The limiter waits out the backoff, giving up when the context does.
+	shortCtx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if _, err := client.Elements.ListElements(shortCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	limited = false
+	if _, err := client.Elements.ListElements(ctx); err != nil {
+		t.Fatalf("ListElements failed: %v", err)
+	}
+	if rate := client.RateLimiter.Rate(GroupPolling); rate != 27.5 {
+		t.Errorf("Expected the rate to recover to 27.5, got %v", rate)
+	}
+}
+
+// TestRateLimiterHeaders tests pausing when the rate limit headers report no requests left.
+func TestRateLimiterHeaders(t *testing.T) {
+	l := NewRateLimiter(map[OperationGroup]RateLimit{GroupGeneration: {Rate: 100, Burst: 10}})
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", "60")
+	l.observe(GroupGeneration, resp)
+
+	if delay, ok := l.reserve(GroupGeneration); ok || delay < 59*time.Second {
+		t.Errorf("Expected to wait about a minute, got %v", delay)
+	}
+	if _, ok := l.reserve(GroupPolling); !ok {
+		t.Error("Expected unlimited groups not to wait")
+	}
+}
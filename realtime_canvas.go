@@ -22,12 +22,14 @@ func (s *RealtimeCanvasService) CreateLCMGeneration(ctx context.Context, req Cre
 	var resp CreateLCMGenerationResponse
 	path := "/generations-lcm"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateLCMGeneration request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "RealtimeCanvas",
+		Operation: "CreateLCMGeneration",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating LCM generation failed: %w", err)
 	}
@@ -41,12 +43,14 @@ func (s *RealtimeCanvasService) PerformInstantRefine(ctx context.Context, req Pe
 	var resp PerformInstantRefineResponse
 	path := "/lcm-instant-refine"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating PerformInstantRefine request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "RealtimeCanvas",
+		Operation: "PerformInstantRefine",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("performing instant refine failed: %w", err)
 	}
@@ -60,12 +64,14 @@ func (s *RealtimeCanvasService) PerformInpainting(ctx context.Context, req Perfo
 	var resp PerformInpaintingResponse
 	path := "/lcm-inpainting"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating PerformInpainting request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "RealtimeCanvas",
+		Operation: "PerformInpainting",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("performing inpainting failed: %w", err)
 	}
@@ -79,12 +85,14 @@ func (s *RealtimeCanvasService) PerformAlchemyUpscale(ctx context.Context, req P
 	var resp PerformAlchemyUpscaleResponse
 	path := "/lcm-upscale"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating PerformAlchemyUpscale request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "RealtimeCanvas",
+		Operation: "PerformAlchemyUpscale",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("performing Alchemy Upscale failed: %w", err)
 	}
@@ -261,14 +261,7 @@ func TestPerformAlchemyUpscale(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := PerformAlchemyUpscaleResponse{
-			LCMGenerationJob: &struct {
-				APICreditCost    *int     `json:"apiCreditCost"`
-				GeneratedImageID *string  `json:"generatedImageId"`
-				GenerationID     []string `json:"generationId"`
-				ImageDataURL     []string `json:"imageDataUrl"`
-				RequestTimestamp *string  `json:"requestTimestamp"`
-				VariationID      []string `json:"variationId"`
-			}{
+			LCMGenerationJob: &AlchemyUpscaleJob{
 				APICreditCost:    Ptr(25),
 				GeneratedImageID: Ptr("gen-upscale-001"),
 				GenerationID:     []string{"gen-123"},
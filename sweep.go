@@ -0,0 +1,329 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // generated images are usually JPEGs
+	"image/png"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SweepAxis is one axis of a sweep grid: a named list of variations of the base request.
+type SweepAxis struct {
+	Name   string
+	Values []SweepValue
+}
+
+// SweepValue is one variation along a SweepAxis.
+type SweepValue struct {
+	Label string
+	Apply func(req *CreateGenerationRequest)
+}
+
+// SweepSeeds returns an axis varying the seed.
+func SweepSeeds(seeds ...int) SweepAxis {
+	axis := SweepAxis{Name: "seed"}
+	for _, seed := range seeds {
+		seed := seed
+		axis.Values = append(axis.Values, SweepValue{
+			Label: strconv.Itoa(seed),
+			Apply: func(req *CreateGenerationRequest) { req.Seed = Ptr(seed) },
+		})
+	}
+	return axis
+}
+
+// SweepSchedulers returns an axis varying the scheduler.
+func SweepSchedulers(schedulers ...Scheduler) SweepAxis {
+	axis := SweepAxis{Name: "scheduler"}
+	for _, scheduler := range schedulers {
+		scheduler := scheduler
+		axis.Values = append(axis.Values, SweepValue{
+			Label: string(scheduler),
+			Apply: func(req *CreateGenerationRequest) { req.Scheduler = Ptr(scheduler) },
+		})
+	}
+	return axis
+}
+
+// SweepGuidanceScales returns an axis varying the guidance scale.
+func SweepGuidanceScales(scales ...int) SweepAxis {
+	axis := SweepAxis{Name: "guidance"}
+	for _, scale := range scales {
+		scale := scale
+		axis.Values = append(axis.Values, SweepValue{
+			Label: strconv.Itoa(scale),
+			Apply: func(req *CreateGenerationRequest) { req.GuidanceScale = Ptr(scale) },
+		})
+	}
+	return axis
+}
+
+// SweepPresetStyles returns an axis varying the preset style.
+func SweepPresetStyles(styles ...PresetStyle) SweepAxis {
+	axis := SweepAxis{Name: "style"}
+	for _, style := range styles {
+		style := style
+		axis.Values = append(axis.Values, SweepValue{
+			Label: string(style),
+			Apply: func(req *CreateGenerationRequest) { req.PresetStyle = Ptr(style) },
+		})
+	}
+	return axis
+}
+
+// SweepModels returns an axis varying the model.
+func SweepModels(models ...ModelInfo) SweepAxis {
+	axis := SweepAxis{Name: "model"}
+	for _, model := range models {
+		model := model
+		axis.Values = append(axis.Values, SweepValue{
+			Label: modelLabel(model),
+			Apply: func(req *CreateGenerationRequest) { req.ModelID = Ptr(model.ID) },
+		})
+	}
+	return axis
+}
+
+// Sweep describes a grid of generations: Base varied along X, and along Y if it has values.
+type Sweep struct {
+	Base CreateGenerationRequest
+	X    SweepAxis
+	Y    SweepAxis
+
+	// Concurrency is the number of generations in flight at once; default 2.
+	Concurrency int
+
+	// PollInterval is passed to WaitForGeneration.
+	PollInterval time.Duration
+
+	// CellSize is the width and height of each cell of the contact sheet; default 256.
+	CellSize int
+}
+
+// SweepManifest maps the cells of a sweep grid to the generations made for them.
+type SweepManifest struct {
+	XAxis string      `json:"xAxis"`
+	YAxis string      `json:"yAxis,omitempty"`
+	Cells []SweepCell `json:"cells"`
+}
+
+// SweepCell is one cell of a sweep grid.
+type SweepCell struct {
+	X            int                     `json:"x"`
+	Y            int                     `json:"y"`
+	XLabel       string                  `json:"xLabel"`
+	YLabel       string                  `json:"yLabel,omitempty"`
+	Request      CreateGenerationRequest `json:"request"`
+	GenerationID string                  `json:"generationId,omitempty"`
+	ImageIDs     []string                `json:"imageIds,omitempty"`
+	ImageURLs    []string                `json:"imageUrls,omitempty"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+// SweepResult is the outcome of a sweep.
+type SweepResult struct {
+	Manifest     SweepManifest
+	ContactSheet *image.RGBA
+}
+
+// WriteContactSheet encodes the contact sheet as PNG.
+func (r *SweepResult) WriteContactSheet(w io.Writer) error {
+	return png.Encode(w, r.ContactSheet)
+}
+
+// WriteManifest encodes the manifest as indented JSON.
+func (r *SweepResult) WriteManifest(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.Manifest)
+}
+
+// Expand returns the request for every cell of the grid, row by row.
+func (sw Sweep) Expand() []SweepCell {
+	ys := sw.Y.Values
+	if len(ys) == 0 {
+		ys = []SweepValue{{}}
+	}
+
+	var cells []SweepCell
+	for y, yv := range ys {
+		for x, xv := range sw.X.Values {
+			req := sw.Base
+			if yv.Apply != nil {
+				yv.Apply(&req)
+			}
+			xv.Apply(&req)
+			cells = append(cells, SweepCell{X: x, Y: y, XLabel: xv.Label, YLabel: yv.Label, Request: req})
+		}
+	}
+	return cells
+}
+
+// Sweep submits every combination in sw, waits for them to complete and builds a
+// labeled contact sheet of the first image of each. Failed cells are recorded in
+// the manifest and drawn blank; their errors are joined in the returned error,
+// which accompanies a usable result.
+func (s *ImagesService) Sweep(ctx context.Context, sw Sweep) (*SweepResult, error) {
+	if len(sw.X.Values) == 0 {
+		return nil, errors.New("sweep requires at least one X value")
+	}
+	concurrency := sw.Concurrency
+	if concurrency <= 0 {
+		concurrency = 2
+	}
+
+	cells := sw.Expand()
+	images := make([]image.Image, len(cells))
+	errs := make([]error, len(cells))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range cells {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			images[i], errs[i] = s.sweepCell(ctx, &cells[i], sw.PollInterval)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			cells[i].Error = err.Error()
+			errs[i] = fmt.Errorf("sweep cell %s/%s failed: %w", cells[i].XLabel, cells[i].YLabel, err)
+		}
+	}
+
+	result := &SweepResult{
+		Manifest: SweepManifest{XAxis: sw.X.Name, YAxis: sw.Y.Name, Cells: cells},
+	}
+	result.ContactSheet = contactSheet(sw, cells, images)
+	return result, errors.Join(errs...)
+}
+
+// sweepCell generates cell, records its outputs and returns its first image.
+func (s *ImagesService) sweepCell(ctx context.Context, cell *SweepCell, interval time.Duration) (image.Image, error) {
+	created, err := s.CreateImageGeneration(ctx, cell.Request)
+	if err != nil {
+		return nil, err
+	}
+	cell.GenerationID = created.GenerationID()
+
+	gen, err := s.WaitForGeneration(ctx, cell.GenerationID, interval)
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range gen.Images() {
+		cell.ImageIDs = append(cell.ImageIDs, deref(img.ID))
+		cell.ImageURLs = append(cell.ImageURLs, deref(img.URL))
+	}
+	if len(cell.ImageURLs) == 0 {
+		return nil, fmt.Errorf("generation %s has no images", cell.GenerationID)
+	}
+
+	var buf bytes.Buffer
+	if _, err := s.DownloadImage(ctx, cell.ImageURLs[0], &buf); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("decoding image failed: %w", err)
+	}
+	return img, nil
+}
+
+// Contact sheet layout.
+const (
+	sheetTextScale = 2
+	sheetPadding   = 8
+)
+
+var (
+	sheetBackground = color.RGBA{0x20, 0x20, 0x20, 0xFF}
+	sheetEmptyCell  = color.RGBA{0x40, 0x40, 0x40, 0xFF}
+	sheetText       = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+)
+
+// contactSheet draws images in a grid with the X labels above and Y labels to the left.
+func contactSheet(sw Sweep, cells []SweepCell, images []image.Image) *image.RGBA {
+	size := sw.CellSize
+	if size <= 0 {
+		size = 256
+	}
+	lineHeight := glyphHeight*sheetTextScale + 2*sheetPadding
+
+	cols, rows := len(sw.X.Values), len(sw.Y.Values)
+	if rows == 0 {
+		rows = 1
+	}
+	labelWidth := 0
+	for _, v := range sw.Y.Values {
+		if w := textWidth(v.Label, sheetTextScale); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	if labelWidth > 0 {
+		labelWidth = min(labelWidth, size) + 2*sheetPadding
+	}
+
+	top := 2 * lineHeight // axis names, then X labels
+	sheet := image.NewRGBA(image.Rect(0, 0, labelWidth+cols*(size+sheetPadding)+sheetPadding, top+rows*(size+sheetPadding)))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(sheetBackground), image.Point{}, draw.Src)
+
+	axes := sw.X.Name
+	if sw.Y.Name != "" {
+		axes += " / " + sw.Y.Name
+	}
+	drawText(sheet, sheetPadding, sheetPadding, fitText(axes, sheet.Bounds().Dx()-2*sheetPadding, sheetTextScale), sheetTextScale, sheetText)
+
+	for i, cell := range cells {
+		x := labelWidth + sheetPadding + cell.X*(size+sheetPadding)
+		y := top + cell.Y*(size+sheetPadding)
+		if cell.Y == 0 {
+			drawText(sheet, x, lineHeight+sheetPadding, fitText(cell.XLabel, size, sheetTextScale), sheetTextScale, sheetText)
+		}
+		if cell.X == 0 && cell.YLabel != "" {
+			drawText(sheet, sheetPadding, y, fitText(cell.YLabel, labelWidth-2*sheetPadding, sheetTextScale), sheetTextScale, sheetText)
+		}
+
+		rect := image.Rect(x, y, x+size, y+size)
+		if images[i] == nil {
+			draw.Draw(sheet, rect, image.NewUniform(sheetEmptyCell), image.Point{}, draw.Src)
+			drawText(sheet, x+sheetPadding, y+sheetPadding, "FAILED", sheetTextScale, sheetText)
+			continue
+		}
+		drawScaled(sheet, rect, images[i])
+	}
+	return sheet
+}
+
+// drawScaled draws src into rect, scaled to fit and centered, using nearest-neighbor sampling.
+func drawScaled(dst *image.RGBA, rect image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	scale := min(float64(rect.Dx())/float64(sb.Dx()), float64(rect.Dy())/float64(sb.Dy()))
+	w, h := int(float64(sb.Dx())*scale), int(float64(sb.Dy())*scale)
+	ox, oy := rect.Min.X+(rect.Dx()-w)/2, rect.Min.Y+(rect.Dy()-h)/2
+	for y := 0; y < h; y++ {
+		sy := sb.Min.Y + int(float64(y)/scale)
+		for x := 0; x < w; x++ {
+			dst.Set(ox+x, oy+y, src.At(sb.Min.X+int(float64(x)/scale), sy))
+		}
+	}
+}
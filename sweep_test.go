@@ -0,0 +1,133 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestSweep tests expanding, generating and assembling a sweep grid.
+func TestSweep(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]CreateGenerationRequest{}
+
+	// Mock server setup
+	var server *httptest.Server
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/generations" && r.Method == "POST":
+			var req CreateGenerationRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if *req.Seed == 13 {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(APIErrorResponse{Code: "internal", Message: "Unlucky seed."})
+				return
+			}
+			id := fmt.Sprintf("gen-%d-%s", *req.Seed, *req.Scheduler)
+			requests[id] = req
+			json.NewEncoder(w).Encode(CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr(id)}})
+		case strings.HasPrefix(r.URL.Path, "/generations/"):
+			id := strings.TrimPrefix(r.URL.Path, "/generations/")
+			json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{
+				ID:              Ptr(id),
+				Status:          Ptr(GenerationStatusComplete),
+				GeneratedImages: []GeneratedImage{{ID: Ptr("img-" + id), URL: Ptr(server.URL + "/images/" + id + ".png")}},
+			}})
+		case strings.HasPrefix(r.URL.Path, "/images/"):
+			img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+			for i := range img.Pix {
+				img.Pix[i] = 0xFF
+			}
+			png.Encode(w, img)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server = httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	sweep := Sweep{
+		Base:         CreateGenerationRequest{Prompt: "A lighthouse.", NumImages: Ptr(1)},
+		X:            SweepSeeds(1, 2, 13),
+		Y:            SweepSchedulers(SchedulerLeonardo, SchedulerDDIM),
+		Concurrency:  3,
+		PollInterval: 1,
+		CellSize:     64,
+	}
+	result, err := client.Images.Sweep(context.Background(), sweep)
+	if err == nil || !strings.Contains(err.Error(), "Unlucky seed.") {
+		t.Errorf("Expected the failed cells to be reported, got %v", err)
+	}
+	if result == nil {
+		t.Fatal("Expected a result alongside the cell errors")
+	}
+
+	cells := result.Manifest.Cells
+	if len(cells) != 6 || len(requests) != 4 {
+		t.Fatalf("Expected 6 cells and 4 generations, got %d cells and %d generations", len(cells), len(requests))
+	}
+	cell := cells[4] // seed 2, DDIM
+	if cell.X != 1 || cell.Y != 1 || cell.XLabel != "2" || cell.YLabel != "DDIM" {
+		t.Errorf("Unexpected cell: %+v", cell)
+	}
+	if cell.GenerationID != "gen-2-DDIM" || len(cell.ImageIDs) != 1 || cell.ImageIDs[0] != "img-gen-2-DDIM" {
+		t.Errorf("Unexpected cell outputs: %+v", cell)
+	}
+	if req := requests["gen-2-DDIM"]; req.Prompt != "A lighthouse." || *req.NumImages != 1 {
+		t.Errorf("Expected the base request to be kept, got %+v", req)
+	}
+	if cells[2].Error == "" || cells[2].GenerationID != "" {
+		t.Errorf("Expected the seed 13 cell to have failed, got %+v", cells[2])
+	}
+
+	sheet := result.ContactSheet
+	if sheet.Bounds().Dx() < 3*64 || sheet.Bounds().Dy() < 2*64 {
+		t.Fatalf("Contact sheet too small: %v", sheet.Bounds())
+	}
+	// The centre of each successful cell holds the white test image.
+	white := color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	labelWidth := textWidth("LEONARDO", sheetTextScale) + 2*sheetPadding
+	top := 2 * (glyphHeight*sheetTextScale + 2*sheetPadding)
+	centre := func(x, y int) color.RGBA {
+		return sheet.RGBAAt(labelWidth+sheetPadding+x*(64+sheetPadding)+32, top+y*(64+sheetPadding)+32)
+	}
+	if centre(1, 1) != white {
+		t.Errorf("Expected the image in cell (1, 1), got %v", centre(1, 1))
+	}
+	if centre(2, 0) != sheetEmptyCell {
+		t.Errorf("Expected the failed cell to be blank, got %v", centre(2, 0))
+	}
+
+	var buf bytes.Buffer
+	if err := result.WriteContactSheet(&buf); err != nil {
+		t.Fatalf("WriteContactSheet failed: %v", err)
+	}
+	buf.Reset()
+	if err := result.WriteManifest(&buf); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"xAxis": "seed"`) {
+		t.Errorf("Unexpected manifest: %s", buf.String())
+	}
+}
@@ -22,12 +22,14 @@ func (s *TextureService) CreateTextureGeneration(ctx context.Context, req Create
 	var resp CreateTextureGenerationResponse
 	path := "/generations-texture"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateTextureGeneration request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Texture",
+		Operation: "CreateTextureGeneration",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating texture generation failed: %w", err)
 	}
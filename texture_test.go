@@ -35,10 +35,7 @@ func TestCreateTextureGeneration(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := CreateTextureGenerationResponse{
-			TextureGenerationJob: struct {
-				APICreditCost *int    `json:"apiCreditCost"`
-				ID            *string `json:"id"`
-			}{
+			TextureGenerationJob: TextureGenerationJob{
 				APICreditCost: Ptr(12),
 				ID:            Ptr("texture-job-001"),
 			},
@@ -23,12 +23,14 @@ func (s *ThreeDModelAssetsService) Upload3DModel(ctx context.Context, req Upload
 	var resp Upload3DModelResponse
 	path := "/models-3d/upload"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating Upload3DModel request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "ThreeDModelAssets",
+		Operation: "Upload3DModel",
+		Method:    http.MethodPost,
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("uploading 3D model failed: %w", err)
 	}
@@ -56,12 +58,14 @@ func (s *ThreeDModelAssetsService) Get3DModelsByUser(ctx context.Context, userID
 
 	path := fmt.Sprintf("/models-3d/user/%s%s", url.PathEscape(userID), queryString)
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating Get3DModelsByUser request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "ThreeDModelAssets",
+		Operation: "Get3DModelsByUser",
+		Method:    "GET",
+		Path:      path,
+		ID:        userID,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving 3D models by user ID failed: %w", err)
 	}
@@ -75,12 +79,14 @@ func (s *ThreeDModelAssetsService) Get3DModelByID(ctx context.Context, id string
 	var resp Get3DModelByIDResponse
 	path := fmt.Sprintf("/models-3d/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating Get3DModelByID request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "ThreeDModelAssets",
+		Operation: "Get3DModelByID",
+		Method:    "GET",
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving 3D model by ID failed: %w", err)
 	}
@@ -94,12 +100,14 @@ func (s *ThreeDModelAssetsService) Delete3DModel(ctx context.Context, id string)
 	var resp Delete3DModelResponse
 	path := fmt.Sprintf("/models-3d/%s", urlPathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating Delete3DModel request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "ThreeDModelAssets",
+		Operation: "Delete3DModel",
+		Method:    http.MethodDelete,
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("deleting 3D model failed: %w", err)
 	}
@@ -38,12 +38,7 @@ func TestUpload3DModel(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := Upload3DModelResponse{
-			UploadModelAsset: &struct {
-				ModelFields *string `json:"modelFields"`
-				ModelID     *string `json:"modelId"`
-				ModelKey    *string `json:"modelKey"`
-				ModelURL    *string `json:"modelUrl"`
-			}{
+			UploadModelAsset: &ModelAssetUpload{
 				ModelFields: Ptr("model-policy-string"),
 				ModelID:     Ptr("model3d-001"),
 				ModelKey:    Ptr("uploads/models-3d/model3d-001.glb"),
@@ -112,20 +107,13 @@ func TestGet3DModelsByUser(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := Get3DModelsByUserResponse{
-			ModelAssets: []struct {
-				CreatedAt *Time   `json:"createdAt"`
-				ID        *string `json:"id"`
-				MeshURL   *string `json:"meshUrl"`
-				Name      *string `json:"name"`
-				UpdatedAt *Time   `json:"updatedAt"`
-				UserID    *string `json:"userId"`
-			}{
+			ModelAssets: []ModelAssetSummary{
 				{
-					CreatedAt: &Time{time.Now().Add(-48 * time.Hour)},
+					CreatedAt: &Time{Time: time.Now().Add(-48 * time.Hour)},
 					ID:        Ptr("model3d-001"),
 					MeshURL:   Ptr("https://s3.amazonaws.com/bucket/uploads/models-3d/model3d-001.glb"),
 					Name:      Ptr("Sample 3D Model"),
-					UpdatedAt: &Time{time.Now().Add(-24 * time.Hour)},
+					UpdatedAt: &Time{Time: time.Now().Add(-24 * time.Hour)},
 					UserID:    Ptr("user-123"),
 				},
 			},
@@ -187,21 +175,13 @@ func TestGet3DModelByID(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := Get3DModelByIDResponse{
-			ModelAssetsByPK: struct {
-				CreatedAt   *Time   `json:"createdAt"`
-				Description *string `json:"description"`
-				ID          *string `json:"id"`
-				MeshURL     *string `json:"meshUrl"`
-				Name        *string `json:"name"`
-				UpdatedAt   *Time   `json:"updatedAt"`
-				UserID      *string `json:"userId"`
-			}{
-				CreatedAt:   &Time{time.Now().Add(-48 * time.Hour)},
+			ModelAssetsByPK: ModelAsset{
+				CreatedAt:   &Time{Time: time.Now().Add(-48 * time.Hour)},
 				Description: Ptr("A detailed 3D model of a futuristic vehicle."),
 				ID:          Ptr("model3d-001"),
 				MeshURL:     Ptr("https://s3.amazonaws.com/bucket/uploads/models-3d/model3d-001.glb"),
 				Name:        Ptr("Futuristic Vehicle"),
-				UpdatedAt:   &Time{time.Now().Add(-24 * time.Hour)},
+				UpdatedAt:   &Time{Time: time.Now().Add(-24 * time.Hour)},
 				UserID:      Ptr("user-123"),
 			},
 		}
@@ -259,9 +239,7 @@ func TestDelete3DModel(t *testing.T) {
 		// Mock successful deletion response
 		w.Header().Set("Content-Type", "application/json")
 		response := Delete3DModelResponse{
-			DeleteModelAssetsByPK: struct {
-				ID *string `json:"id"`
-			}{
+			DeleteModelAssetsByPK: ResourceID{
 				ID: Ptr(expectedID),
 			},
 		}
@@ -0,0 +1,70 @@
+package leonardo
+
+import "context"
+
+// Tracer starts spans for client operations. It is deliberately small so it can be
+// adapted to OpenTelemetry or another tracing library without this package
+// depending on it.
+//
+// The client starts a span named after each service call (e.g.
+// "Images.CreateImageGeneration") and for workflows such as
+// "Images.WaitForGeneration", "Images.DownloadImage" and "S3.Upload". Calls made
+// within a workflow are started from the workflow's context, so they nest under it.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key/value pair attached to a Span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr returns an Attribute.
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+type spanContextKey struct{}
+
+// startSpan starts a span on c.Tracer, or a no-op span when no tracer is set.
+// The span is stored in the returned context so Client.Do can annotate it.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if c.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := c.Tracer.Start(ctx, name)
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// spanFromContext returns the innermost span started by the client for ctx.
+func spanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanContextKey{}).(Span); ok {
+		return span
+	}
+	return noopSpan{}
+}
+
+// endSpan records err, if any, and ends the span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
@@ -0,0 +1,155 @@
+package leonardo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTracer records the spans it starts along with their parents.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+type recordedSpanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, _ := ctx.Value(recordedSpanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+// TestTracingWaitAndDownload tests the spans started for calls and workflows.
+func TestTracingWaitAndDownload(t *testing.T) {
+	polls := 0
+
+	// Mock server setup
+	var server *httptest.Server
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/generations/gen-123":
+			polls++
+			status := "PENDING"
+			if polls == 3 {
+				status = "COMPLETE"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"generations_by_pk": map[string]interface{}{
+					"id":               "gen-123",
+					"status":           status,
+					"generated_images": []interface{}{map[string]interface{}{"id": "img-1", "url": server.URL + "/img-1.png"}},
+				},
+			})
+		case "/img-1.png":
+			w.Write([]byte("PNGDATA"))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server = httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	tracer := &recordingTracer{}
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+		Tracer:     tracer,
+	}
+	client.Images = client.NewImagesService()
+
+	ctx := context.Background()
+	gen, err := client.Images.WaitForGeneration(ctx, "gen-123", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForGeneration failed: %v", err)
+	}
+	if *gen.GenerationsByPK.Status != GenerationStatusComplete {
+		t.Errorf("Expected status COMPLETE, got %s", *gen.GenerationsByPK.Status)
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.Images.DownloadImage(ctx, *gen.GenerationsByPK.GeneratedImages[0].URL, &buf); err != nil {
+		t.Fatalf("DownloadImage failed: %v", err)
+	}
+	if buf.String() != "PNGDATA" {
+		t.Errorf("Expected downloaded data 'PNGDATA', got %q", buf.String())
+	}
+
+	if len(tracer.spans) != 5 {
+		t.Fatalf("Expected 5 spans, got %d", len(tracer.spans))
+	}
+	wait := tracer.spans[0]
+	if wait.name != "Images.WaitForGeneration" || wait.attrs["leonardo.polls"] != 3 || !wait.ended {
+		t.Errorf("Unexpected wait span: %+v", wait)
+	}
+	for _, poll := range tracer.spans[1:4] {
+		if poll.name != "Images.GetImageGeneration" || poll.parent != wait {
+			t.Errorf("Expected poll span nested under the wait span, got %s (parent %v)", poll.name, poll.parent)
+		}
+		if poll.attrs["http.status_code"] != 200 || poll.attrs["leonardo.id"] != "gen-123" {
+			t.Errorf("Unexpected poll span attributes: %v", poll.attrs)
+		}
+	}
+	download := tracer.spans[4]
+	if download.name != "Images.DownloadImage" || download.attrs["leonardo.bytes"] != int64(7) {
+		t.Errorf("Unexpected download span: %+v", download)
+	}
+}
+
+// TestTracingRecordsErrors tests that failed calls record the error on their span.
+func TestTracingRecordsErrors(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(APIErrorResponse{Code: "internal", Message: "Internal error."})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	tracer := &recordingTracer{}
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+		Tracer:     tracer,
+	}
+	client.Elements = client.NewElementsService()
+
+	if _, err := client.Elements.ListElements(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if len(tracer.spans) != 1 || tracer.spans[0].err == nil || tracer.spans[0].attrs["http.status_code"] != 500 {
+		t.Errorf("Expected one span with a recorded error and status 500, got %+v", tracer.spans)
+	}
+}
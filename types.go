@@ -1,8 +1,8 @@
 package leonardo
 
 import (
+	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -13,35 +13,113 @@ type APIErrorResponse struct {
 	Path    string `json:"path"`
 }
 
+// APIError is returned when the API responds with a non-2xx status code.
+// Use errors.As to inspect the status code of a failed call.
+type APIError struct {
+	StatusCode int
+	APIErrorResponse
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API Error %d: %s", e.StatusCode, e.Message)
+}
+
 // Helper function to create pointers
 func Ptr[T any](v T) *T {
 	return &v
 }
 
+// Time is a timestamp returned by the API. It accepts every format the API has
+// been observed to send: with or without milliseconds (or longer fractions), with
+// or without a zone (UTC is assumed when absent), and null or empty values, which
+// leave it zero. A Time marshals back to the exact text it was parsed from unless
+// the Time field has been changed since.
 type Time struct {
-	Time time.Time
+	Time   time.Time
+	raw    string
+	parsed bool // set when parsed from raw, which may be empty
+}
+
+// timeLayout is the format the API most commonly uses, and the one used to
+// marshal a Time that wasn't parsed from JSON.
+const timeLayout = "2006-01-02T15:04:05.000"
+
+// timeLayouts are tried in order when parsing. Fractional seconds of any length
+// are accepted after the seconds field even though the layouts omit them.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTime parses a timestamp in any of the formats accepted by Time.
+func ParseTime(s string) (Time, error) {
+	if s == "" {
+		return Time{parsed: true}, nil
+	}
+	for _, layout := range timeLayouts {
+		if pt, err := time.Parse(layout, s); err == nil {
+			return Time{Time: pt, raw: s, parsed: true}, nil
+		}
+	}
+	return Time{}, fmt.Errorf("parsing time %q failed: unrecognized format", s)
+}
+
+// IsZero reports whether t is unset, e.g. because the API returned null.
+func (t Time) IsZero() bool {
+	return t.Time.IsZero()
+}
+
+// Raw returns the text t was parsed from, or "" if it wasn't parsed.
+func (t Time) Raw() string {
+	return t.raw
+}
+
+func (t Time) String() string {
+	if t.parsed && t.unchanged() {
+		return t.raw
+	}
+	return t.Time.UTC().Format(timeLayout)
 }
 
-func (t *Time) String() string {
-	return t.Time.Format("2006-01-02T15:04:05.000")
+// unchanged reports whether t.Time still holds the value parsed from t.raw.
+func (t Time) unchanged() bool {
+	pt, err := ParseTime(t.raw)
+	return err == nil && pt.Time.Equal(t.Time)
 }
 
 func (t *Time) UnmarshalJSON(data []byte) error {
-	// Remove quotes from string
-	s := strings.Trim(string(data), "\"")
+	if string(data) == "null" {
+		*t = Time{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
 
-	// Parse the time string
-	pt, err := time.Parse("2006-01-02T15:04:05.000", s)
+	pt, err := ParseTime(s)
 	if err != nil {
 		return err
 	}
 
-	t.Time = pt
+	*t = pt
 	return nil
 }
 
 func (t Time) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf("\"%s\"", t.Time.Format("2006-01-02T15:04:05.000"))), nil
+	if t.IsZero() && !t.parsed {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
 }
 
 // Dataset-related types
@@ -52,34 +130,46 @@ type CreateDatasetRequest struct {
 	Description *string `json:"description,omitempty"`
 }
 
+// ResourceID identifies the resource created or deleted by a call.
+type ResourceID struct {
+	ID *string `json:"id"`
+}
+
 // CreateDatasetResponse represents the response after creating a dataset.
 type CreateDatasetResponse struct {
-	InsertDatasetsOne struct {
-		ID *string `json:"id"`
-	} `json:"insert_datasets_one"`
+	InsertDatasetsOne ResourceID `json:"insert_datasets_one"`
+}
+
+// DatasetID returns the ID of the created dataset, or "" if absent.
+func (r *CreateDatasetResponse) DatasetID() string {
+	return deref(r.InsertDatasetsOne.ID)
 }
 
 // GetDatasetResponse represents the response when retrieving a dataset by ID.
 type GetDatasetResponse struct {
-	DatasetsByPk struct {
-		CreatedAt     *Time `json:"createdAt"`
-		DatasetImages []struct {
-			CreatedAt *Time   `json:"createdAt"`
-			ID        *string `json:"id"`
-			URL       *string `json:"url"`
-		} `json:"dataset_images"`
-		Description *string `json:"description"`
-		ID          *string `json:"id"`
-		Name        *string `json:"name"`
-		UpdatedAt   *Time   `json:"updatedAt"`
-	} `json:"datasets_by_pk"`
+	DatasetsByPk Dataset `json:"datasets_by_pk"`
+}
+
+// Dataset represents a dataset and its images.
+type Dataset struct {
+	CreatedAt     *Time          `json:"createdAt"`
+	DatasetImages []DatasetImage `json:"dataset_images"`
+	Description   *string        `json:"description"`
+	ID            *string        `json:"id"`
+	Name          *string        `json:"name"`
+	UpdatedAt     *Time          `json:"updatedAt"`
+}
+
+// DatasetImage represents an image in a dataset.
+type DatasetImage struct {
+	CreatedAt *Time   `json:"createdAt"`
+	ID        *string `json:"id"`
+	URL       *string `json:"url"`
 }
 
 // DeleteDatasetResponse represents the response after deleting a dataset.
 type DeleteDatasetResponse struct {
-	DeleteDatasetsByPK struct {
-		ID *string `json:"id"`
-	} `json:"delete_datasets_by_pk"`
+	DeleteDatasetsByPK ResourceID `json:"delete_datasets_by_pk"`
 }
 
 // UploadDatasetImageRequest represents the payload for uploading a dataset image to S3.
@@ -89,12 +179,15 @@ type UploadDatasetImageRequest struct {
 
 // UploadDatasetImageResponse represents the response containing presigned S3 upload details.
 type UploadDatasetImageResponse struct {
-	UploadDatasetImage *struct {
-		Fields map[string]string `json:"fields"`
-		ID     *string           `json:"id"`
-		Key    *string           `json:"key"`
-		URL    *string           `json:"url"`
-	} `json:"uploadDatasetImage"`
+	UploadDatasetImage *DatasetImageUpload `json:"uploadDatasetImage"`
+}
+
+// DatasetImageUpload holds the presigned S3 details for uploading a dataset image.
+type DatasetImageUpload struct {
+	Fields map[string]string `json:"fields"`
+	ID     *string           `json:"id"`
+	Key    *string           `json:"key"`
+	URL    *string           `json:"url"`
 }
 
 // UploadGeneratedImageRequest represents the payload for uploading a generated image to a dataset.
@@ -104,53 +197,58 @@ type UploadGeneratedImageRequest struct {
 
 // UploadGeneratedImageResponse represents the response after uploading a generated image to a dataset.
 type UploadGeneratedImageResponse struct {
-	UploadDatasetImageFromGen *struct {
-		ID *string `json:"id"`
-	} `json:"uploadDatasetImageFromGen"`
+	UploadDatasetImageFromGen *ResourceID `json:"uploadDatasetImageFromGen"`
 }
 
 // Image Generation-related types
 type CreateGenerationRequest struct {
-	Alchemy               *bool              `json:"alchemy,omitempty"`       // default true
-	ContrastRatio         *float64           `json:"contrastRatio,omitempty"` // 0.1-1.0 inclusive
-	ExpandedDomain        *bool              `json:"expandedDomain,omitempty"`
-	FantasyAvatar         *bool              `json:"fantasyAvatar,omitempty"`
-	GuidanceScale         *int               `json:"guidance_scale,omitempty"` // 1-20, recommended 7
-	Height                *int               `json:"height,omitempty"`         // default 768
-	HighContrast          *bool              `json:"highContrast,omitempty"`
-	HighResolution        *bool              `json:"highResolution,omitempty"`
-	ImagePrompts          []string           `json:"imagePrompts,omitempty"`
-	ImagePromptWeight     *float64           `json:"imagePromptWeight,omitempty"`
-	InitGenerationImageID *string            `json:"init_generation_image_id,omitempty"`
-	InitImageID           *string            `json:"init_image_id,omitempty"`
-	InitStrength          *float64           `json:"init_strength,omitempty"`
-	ModelID               *string            `json:"modelId,omitempty"` // default b24e16ff-06e3-43eb-8d33-4416c2d75876
-	NegativePrompt        *string            `json:"negative_prompt,omitempty"`
-	NumImages             *int               `json:"num_images,omitempty"`          // default 4
-	NumInferenceSteps     *int               `json:"num_inference_steps,omitempty"` // 10-60, default 15
-	PhotoReal             *bool              `json:"photoReal,omitempty"`           // requires Alchemy=true, ModelID=nil
-	PhotoRealVersion      *string            `json:"photoRealVersion,omitempty"`    // v1 or v2
-	PhotoRealStrength     *float64           `json:"photoRealStrength,omitempty"`   // 0.55 for low, 0.5 for medium, 0.45 for high; default 0.55
-	PresetStyle           *PresetStyle       `json:"presetStyle,omitempty"`         // default DYNAMIC
-	Prompt                string             `json:"prompt"`                        // required
-	PromptMagic           *bool              `json:"promptMagic,omitempty"`
-	PromptMagicStrength   *float64           `json:"promptMagicStrength,omitempty"` // 0.1-1.0 inclusive
-	PromptMagicVersion    *string            `json:"promptMagicVersion,omitempty"`  // v2 or v3
-	Public                *bool              `json:"public,omitempty"`
-	Scheduler             *Scheduler         `json:"scheduler,omitempty"`  // default EULER_DISCRETE
-	SDVersion             *SDVersion         `json:"sd_version,omitempty"` // default v1_5
-	Seed                  *int               `json:"seed,omitempty"`
-	Tiling                *bool              `json:"tiling,omitempty"`
-	Transparency          *string            `json:"transparency,omitempty"` // disabled, foreground_only; default disabled
-	Ultra                 *bool              `json:"ultra,omitempty"`        // requires Alchemy=false
-	Unzoom                *bool              `json:"unzoom,omitempty"`       // requires UnzoomAmount and InitImageID
-	UnzoomAmount          *int               `json:"unzoomAmount,omitempty"`
-	UpscaleRatio          *int               `json:"upscaleRatio,omitempty"` // NOTE: ENTERPRISE ACCOUNTS ONLY
-	Width                 *int               `json:"width,omitempty"`        // 32-1024; default 1024
-	CanvasRequest         *bool              `json:"canvasRequest,omitempty"`
-	CanvasRequestType     *CanvasRequestType `json:"canvasRequestType,omitempty"` // INPAINT, OUTPAINT, SKETCH2IMG, IMG2IMG
-	CanvasInitID          *string            `json:"canvasInitId,omitempty"`
-	CanvasMaskID          *string            `json:"canvasMaskId,omitempty"`
+	Alchemy               *bool               `json:"alchemy,omitempty"`       // default true
+	ContrastRatio         *float64            `json:"contrastRatio,omitempty"` // 0.1-1.0 inclusive
+	ExpandedDomain        *bool               `json:"expandedDomain,omitempty"`
+	FantasyAvatar         *bool               `json:"fantasyAvatar,omitempty"`
+	GuidanceScale         *int                `json:"guidance_scale,omitempty"` // 1-20, recommended 7
+	Height                *int                `json:"height,omitempty"`         // default 768
+	HighContrast          *bool               `json:"highContrast,omitempty"`
+	HighResolution        *bool               `json:"highResolution,omitempty"`
+	ImagePrompts          []string            `json:"imagePrompts,omitempty"`
+	ImagePromptWeight     *float64            `json:"imagePromptWeight,omitempty"`
+	InitGenerationImageID *string             `json:"init_generation_image_id,omitempty"`
+	InitImageID           *string             `json:"init_image_id,omitempty"`
+	InitStrength          *float64            `json:"init_strength,omitempty"`
+	ModelID               *string             `json:"modelId,omitempty"` // default DefaultModelID; see ModelByName
+	NegativePrompt        *string             `json:"negative_prompt,omitempty"`
+	NumImages             *int                `json:"num_images,omitempty"`          // default 4
+	NumInferenceSteps     *int                `json:"num_inference_steps,omitempty"` // 10-60, default 15
+	PhotoReal             *bool               `json:"photoReal,omitempty"`           // requires Alchemy=true, ModelID=nil
+	PhotoRealVersion      *PhotoRealVersion   `json:"photoRealVersion,omitempty"`    // v1 or v2
+	PhotoRealStrength     *float64            `json:"photoRealStrength,omitempty"`   // 0.55 for low, 0.5 for medium, 0.45 for high; default 0.55
+	PresetStyle           *PresetStyle        `json:"presetStyle,omitempty"`         // default DYNAMIC
+	Prompt                string              `json:"prompt"`                        // required
+	PromptMagic           *bool               `json:"promptMagic,omitempty"`
+	PromptMagicStrength   *float64            `json:"promptMagicStrength,omitempty"` // 0.1-1.0 inclusive
+	PromptMagicVersion    *PromptMagicVersion `json:"promptMagicVersion,omitempty"`  // v2 or v3
+	Public                *bool               `json:"public,omitempty"`
+	Scheduler             *Scheduler          `json:"scheduler,omitempty"`  // default EULER_DISCRETE
+	SDVersion             *SDVersion          `json:"sd_version,omitempty"` // default v1_5
+	Seed                  *int                `json:"seed,omitempty"`
+	Tiling                *bool               `json:"tiling,omitempty"`
+	Transparency          *Transparency       `json:"transparency,omitempty"` // disabled, foreground_only; default disabled
+	Ultra                 *bool               `json:"ultra,omitempty"`        // requires Alchemy=false
+	Unzoom                *bool               `json:"unzoom,omitempty"`       // requires UnzoomAmount and InitImageID
+	UnzoomAmount          *int                `json:"unzoomAmount,omitempty"`
+	UpscaleRatio          *int                `json:"upscaleRatio,omitempty"` // NOTE: ENTERPRISE ACCOUNTS ONLY
+	Width                 *int                `json:"width,omitempty"`        // 32-1024; default 1024
+	CanvasRequest         *bool               `json:"canvasRequest,omitempty"`
+	CanvasRequestType     *CanvasRequestType  `json:"canvasRequestType,omitempty"` // INPAINT, OUTPAINT, SKETCH2IMG, IMG2IMG
+	CanvasInitID          *string             `json:"canvasInitId,omitempty"`
+	CanvasMaskID          *string             `json:"canvasMaskId,omitempty"`
+	Elements              []ElementWeight     `json:"elements,omitempty"`
+}
+
+// ElementWeight applies an element (LoRA) to a generation.
+type ElementWeight struct {
+	AKUUID string  `json:"akUUID"`
+	Weight float64 `json:"weight"`
 }
 
 type PresetStyle string
@@ -211,6 +309,7 @@ const (
 	SDVersionSDXL_0_9       SDVersion = "SDXL_0_9"
 	SDVersionSDXL_1_0       SDVersion = "SDXL_1_0"
 	SDVersionSDXL_LIGHTNING SDVersion = "SDXL_LIGHTNING"
+	SDVersionPhoenix        SDVersion = "PHOENIX"
 )
 
 type CanvasRequestType string
@@ -222,59 +321,117 @@ const (
 	CanvasRequestTypeImg2Img    CanvasRequestType = "IMG2IMG"
 )
 
+type PhotoRealVersion string
+
+const (
+	PhotoRealVersionV1 PhotoRealVersion = "v1"
+	PhotoRealVersionV2 PhotoRealVersion = "v2"
+)
+
+type PromptMagicVersion string
+
+const (
+	PromptMagicVersionV2 PromptMagicVersion = "v2"
+	PromptMagicVersionV3 PromptMagicVersion = "v3"
+)
+
+type Transparency string
+
+const (
+	TransparencyDisabled       Transparency = "disabled"
+	TransparencyForegroundOnly Transparency = "foreground_only"
+)
+
 type CreateGenerationResponse struct {
-	SDGenerationJob struct {
-		APICreditCost *int    `json:"apiCreditCost"`
-		GenerationID  *string `json:"generationId"`
-	} `json:"sdGenerationJob"`
+	SDGenerationJob GenerationJob `json:"sdGenerationJob"`
+}
+
+// GenerationJob represents a submitted image generation job.
+type GenerationJob struct {
+	APICreditCost *int    `json:"apiCreditCost"`
+	GenerationID  *string `json:"generationId"`
+}
+
+// GenerationID returns the ID of the submitted generation, or "" if absent.
+func (r *CreateGenerationResponse) GenerationID() string {
+	return deref(r.SDGenerationJob.GenerationID)
+}
+
+// APICreditCost returns the API credit cost of the submitted generation.
+func (r *CreateGenerationResponse) APICreditCost() int {
+	return deref(r.SDGenerationJob.APICreditCost)
 }
 
 type GetGenerationResponse struct {
-	GenerationsByPK struct {
-		ID                  *string           `json:"id"`
-		Status              *GenerationStatus `json:"status"`
-		CreatedAt           *Time             `json:"createdAt"`
-		Height              *int              `json:"imageHeight,omitempty"`
-		ModelID             *string           `json:"modelId,omitempty"`
-		NegativePrompt      *string           `json:"negativePrompt,omitempty"`
-		NumInferenceSteps   *int              `json:"inferenceSteps,omitempty"`
-		PhotoReal           *bool             `json:"photoReal,omitempty"`
-		PhotoRealStrength   *float64          `json:"photoRealStrength,omitempty"`
-		PresetStyle         *PresetStyle      `json:"presetStyle,omitempty"`
-		Prompt              string            `json:"prompt"`
-		PromptMagic         *bool             `json:"promptMagic,omitempty"`
-		PromptMagicStrength *float64          `json:"promptMagicStrength,omitempty"`
-		PromptMagicVersion  *string           `json:"promptMagicVersion,omitempty"`
-		Public              *bool             `json:"public,omitempty"`
-		Scheduler           *Scheduler        `json:"scheduler,omitempty"`
-		SDVersion           *SDVersion        `json:"sdVersion,omitempty"`
-		Seed                *int              `json:"seed,omitempty"`
-		Ultra               *bool             `json:"ultra,omitempty"`
-		Width               *int              `json:"imageWidth,omitempty"`
-		GenerationElements  []struct {
-			ID            *string `json:"id"`
-			Lora          *Lora   `json:"lora"`
-			WeightApplied *int    `json:"weightApplied"`
-		} `json:"generation_elements"`
-		GeneratedImages []struct {
-			ID                              *string `json:"id"`
-			GeneratedImageVariationGenerics []struct {
-				ID            *string           `json:"id"`
-				Status        *GenerationStatus `json:"status"`
-				TransformType *TransformType    `json:"transformType"`
-				URL           *string           `json:"url"`
-			} `json:"generated_image_variation_generics"`
-			FantasyAvatar  *bool   `json:"fantasyAvatar,omitempty"`
-			ImageToVideo   *bool   `json:"imageToVideo,omitempty"`
-			LikeCount      *int    `json:"likeCount,omitempty"`
-			Motion         *bool   `json:"motion,omitempty"`
-			MotionModel    *string `json:"motionModel,omitempty"`
-			MotionMP4URL   *string `json:"motionMP4Url,omitempty"`
-			MotionStrength *int    `json:"motionStrength,omitempty"`
-			NSFW           *bool   `json:"nsfw,omitempty"`
-			URL            *string `json:"url"`
-		} `json:"generated_images"`
-	} `json:"generations_by_pk"`
+	GenerationsByPK GenerationDetails `json:"generations_by_pk"`
+}
+
+// Status returns the status of the generation, or "" if absent.
+func (r *GetGenerationResponse) Status() GenerationStatus {
+	return deref(r.GenerationsByPK.Status)
+}
+
+// Images returns the generated images.
+func (r *GetGenerationResponse) Images() []GeneratedImage {
+	return r.GenerationsByPK.GeneratedImages
+}
+
+// GenerationDetails represents a generation with the settings it was generated with.
+type GenerationDetails struct {
+	ID                  *string             `json:"id"`
+	Status              *GenerationStatus   `json:"status"`
+	CreatedAt           *Time               `json:"createdAt"`
+	GuidanceScale       *int                `json:"guidanceScale,omitempty"`
+	Height              *int                `json:"imageHeight,omitempty"`
+	InitStrength        *float64            `json:"initStrength,omitempty"`
+	ModelID             *string             `json:"modelId,omitempty"`
+	NegativePrompt      *string             `json:"negativePrompt,omitempty"`
+	NumInferenceSteps   *int                `json:"inferenceSteps,omitempty"`
+	PhotoReal           *bool               `json:"photoReal,omitempty"`
+	PhotoRealStrength   *float64            `json:"photoRealStrength,omitempty"`
+	PresetStyle         *PresetStyle        `json:"presetStyle,omitempty"`
+	Prompt              string              `json:"prompt"`
+	PromptMagic         *bool               `json:"promptMagic,omitempty"`
+	PromptMagicStrength *float64            `json:"promptMagicStrength,omitempty"`
+	PromptMagicVersion  *PromptMagicVersion `json:"promptMagicVersion,omitempty"`
+	Public              *bool               `json:"public,omitempty"`
+	Scheduler           *Scheduler          `json:"scheduler,omitempty"`
+	SDVersion           *SDVersion          `json:"sdVersion,omitempty"`
+	Seed                *int                `json:"seed,omitempty"`
+	Ultra               *bool               `json:"ultra,omitempty"`
+	Width               *int                `json:"imageWidth,omitempty"`
+	GenerationElements  []GenerationElement `json:"generation_elements"`
+	GeneratedImages     []GeneratedImage    `json:"generated_images"`
+}
+
+// GenerationElement represents an element (LoRA) applied to a generation.
+type GenerationElement struct {
+	ID            *string  `json:"id"`
+	Lora          *Lora    `json:"lora"`
+	WeightApplied *float64 `json:"weightApplied"`
+}
+
+// GeneratedImage represents an image produced by a generation.
+type GeneratedImage struct {
+	ID                              *string          `json:"id"`
+	GeneratedImageVariationGenerics []ImageVariation `json:"generated_image_variation_generics"`
+	FantasyAvatar                   *bool            `json:"fantasyAvatar,omitempty"`
+	ImageToVideo                    *bool            `json:"imageToVideo,omitempty"`
+	LikeCount                       *int             `json:"likeCount,omitempty"`
+	Motion                          *bool            `json:"motion,omitempty"`
+	MotionModel                     *string          `json:"motionModel,omitempty"`
+	MotionMP4URL                    *string          `json:"motionMP4Url,omitempty"`
+	MotionStrength                  *int             `json:"motionStrength,omitempty"`
+	NSFW                            *bool            `json:"nsfw,omitempty"`
+	URL                             *string          `json:"url"`
+}
+
+// ImageVariation represents a variation (upscale, unzoom, etc.) of a generated image.
+type ImageVariation struct {
+	ID            *string           `json:"id"`
+	Status        *GenerationStatus `json:"status"`
+	TransformType *TransformType    `json:"transformType"`
+	URL           *string           `json:"url"`
 }
 
 type GenerationStatus string
@@ -296,11 +453,12 @@ const (
 )
 
 type Generation struct {
-	ID     *string `json:"id"`
-	Status *string `json:"status"`
+	ID     *string           `json:"id"`
+	Status *GenerationStatus `json:"status"`
 	// Add other relevant fields as necessary
 	CreatedAt *Time `json:"createdAt,omitempty"`
 	// Depending on the API response, include other fields like prompt, etc.
+	GeneratedImages []GeneratedImage `json:"generated_images,omitempty"`
 }
 
 type GetGenerationsByUserResponse struct {
@@ -308,9 +466,7 @@ type GetGenerationsByUserResponse struct {
 }
 
 type DeleteGenerationResponse struct {
-	DeleteGenerationsByPK struct {
-		ID *string `json:"id"`
-	} `json:"delete_generations_by_pk"`
+	DeleteGenerationsByPK ResourceID `json:"delete_generations_by_pk"`
 }
 
 // Elements-related types
@@ -334,18 +490,34 @@ type Lora struct {
 
 // User-related types
 type GetUserInfoResponse struct {
-	UserDetails []struct {
-		APIPlanTokenRenewalDate *string `json:"apiPlanTokenRenewalDate"`
-		APIConcurrencySlots     *int    `json:"apiConcurrencySlots"`
-		APIPaidTokens           *int    `json:"apiPaidTokens"`
-		APISubscriptionTokens   *int    `json:"apiSubscriptionTokens"`
-		PaidTokens              *int    `json:"paidTokens"`
-		SubscriptionGPTTokens   *int    `json:"subscriptionGptTokens"`
-		SubscriptionModelTokens *int    `json:"subscriptionModelTokens"`
-		SubscriptionTokens      *int    `json:"subscriptionTokens"`
-		TokenRenewalDate        *string `json:"tokenRenewalDate"`
-		User                    User    `json:"user"`
-	} `json:"user_details"`
+	UserDetails []UserDetails `json:"user_details"`
+}
+
+// Details returns the details of the authenticated user, or nil if absent.
+func (r *GetUserInfoResponse) Details() *UserDetails {
+	if len(r.UserDetails) == 0 {
+		return nil
+	}
+	return &r.UserDetails[0]
+}
+
+// UserDetails represents the token balances and plan details of a user.
+type UserDetails struct {
+	APIPlanTokenRenewalDate *Time `json:"apiPlanTokenRenewalDate"`
+	APIConcurrencySlots     *int  `json:"apiConcurrencySlots"`
+	APIPaidTokens           *int  `json:"apiPaidTokens"`
+	APISubscriptionTokens   *int  `json:"apiSubscriptionTokens"`
+	PaidTokens              *int  `json:"paidTokens"`
+	SubscriptionGPTTokens   *int  `json:"subscriptionGptTokens"`
+	SubscriptionModelTokens *int  `json:"subscriptionModelTokens"`
+	SubscriptionTokens      *int  `json:"subscriptionTokens"`
+	TokenRenewalDate        *Time `json:"tokenRenewalDate"`
+	User                    User  `json:"user"`
+}
+
+// APITokens returns the total API tokens (paid and subscription) available.
+func (d *UserDetails) APITokens() int {
+	return deref(d.APIPaidTokens) + deref(d.APISubscriptionTokens)
 }
 
 // User represents the user information.
@@ -360,64 +532,106 @@ type User struct {
 
 // TrainCustomModelRequest represents the payload for training a new custom model.
 type TrainCustomModelRequest struct {
-	DatasetID      string  `json:"datasetId"`
-	Description    *string `json:"description,omitempty"`
-	InstancePrompt string  `json:"instance_prompt"`
-	ModelType      *string `json:"modelType,omitempty"`
-	Name           string  `json:"name"`
-	NSFW           *bool   `json:"nsfw,omitempty"`
-	Resolution     *int    `json:"resolution,omitempty"`
-	SDVersion      *string `json:"sd_Version,omitempty"`
-	Strength       *string `json:"strength,omitempty"`
+	DatasetID      string            `json:"datasetId"`
+	Description    *string           `json:"description,omitempty"`
+	InstancePrompt string            `json:"instance_prompt"`
+	ModelType      *CustomModelType  `json:"modelType,omitempty"`
+	Name           string            `json:"name"`
+	NSFW           *bool             `json:"nsfw,omitempty"`
+	Resolution     *int              `json:"resolution,omitempty"`
+	SDVersion      *SDVersion        `json:"sd_Version,omitempty"`
+	Strength       *TrainingStrength `json:"strength,omitempty"`
 }
 
+// CustomModelType is the category of a custom model.
+type CustomModelType string
+
+const (
+	CustomModelTypeGeneral           CustomModelType = "GENERAL"
+	CustomModelTypeBuildings         CustomModelType = "BUILDINGS"
+	CustomModelTypeCharacters        CustomModelType = "CHARACTERS"
+	CustomModelTypeEnvironments      CustomModelType = "ENVIRONMENTS"
+	CustomModelTypeFashion           CustomModelType = "FASHION"
+	CustomModelTypeIllustrations     CustomModelType = "ILLUSTRATIONS"
+	CustomModelTypeGameItems         CustomModelType = "GAME_ITEMS"
+	CustomModelTypeGraphicalElements CustomModelType = "GRAPHICAL_ELEMENTS"
+	CustomModelTypePhotography       CustomModelType = "PHOTOGRAPHY"
+	CustomModelTypePixelArt          CustomModelType = "PIXEL_ART"
+	CustomModelTypeProductDesign     CustomModelType = "PRODUCT_DESIGN"
+	CustomModelTypeTextures          CustomModelType = "TEXTURES"
+	CustomModelTypeUIElements        CustomModelType = "UI_ELEMENTS"
+	CustomModelTypeVector            CustomModelType = "VECTOR"
+)
+
+// TrainingStrength is how strongly a custom model is trained on its dataset.
+type TrainingStrength string
+
+const (
+	TrainingStrengthVeryLow TrainingStrength = "VERY_LOW"
+	TrainingStrengthLow     TrainingStrength = "LOW"
+	TrainingStrengthMedium  TrainingStrength = "MEDIUM"
+	TrainingStrengthHigh    TrainingStrength = "HIGH"
+)
+
 // TrainCustomModelResponse represents the response from training a new custom model.
 type TrainCustomModelResponse struct {
-	SDTrainingJob struct {
-		APICreditCost *int    `json:"apiCreditCost"`
-		CustomModelID *string `json:"customModelId"`
-	} `json:"sdTrainingJob"`
+	SDTrainingJob TrainingJob `json:"sdTrainingJob"`
+}
+
+// TrainingJob represents a submitted custom model training job.
+type TrainingJob struct {
+	APICreditCost *int    `json:"apiCreditCost"`
+	CustomModelID *string `json:"customModelId"`
+}
+
+// CustomModelID returns the ID of the model being trained, or "" if absent.
+func (r *TrainCustomModelResponse) CustomModelID() string {
+	return deref(r.SDTrainingJob.CustomModelID)
 }
 
 // GetCustomModelResponse represents the response when retrieving a custom model by ID.
 type GetCustomModelResponse struct {
-	CustomModelsByPK struct {
-		CreatedAt      *Time   `json:"createdAt"`
-		Description    *string `json:"description"`
-		ID             *string `json:"id"`
-		InstancePrompt *string `json:"instancePrompt"`
-		ModelHeight    *int    `json:"modelHeight"`
-		ModelWidth     *int    `json:"modelWidth"`
-		Name           *string `json:"name"`
-		Public         *bool   `json:"public"`
-		SDVersion      *string `json:"sdVersion"`
-		Status         *string `json:"status"`
-		Type           *string `json:"type"`
-		UpdatedAt      *Time   `json:"updatedAt"`
-	} `json:"custom_models_by_pk"`
+	CustomModelsByPK CustomModel `json:"custom_models_by_pk"`
+}
+
+// CustomModel represents a custom (trained) model.
+type CustomModel struct {
+	CreatedAt      *Time   `json:"createdAt"`
+	Description    *string `json:"description"`
+	ID             *string `json:"id"`
+	InstancePrompt *string `json:"instancePrompt"`
+	ModelHeight    *int    `json:"modelHeight"`
+	ModelWidth     *int    `json:"modelWidth"`
+	Name           *string `json:"name"`
+	Public         *bool   `json:"public"`
+	SDVersion      *string `json:"sdVersion"`
+	Status         *string `json:"status"`
+	Type           *string `json:"type"`
+	UpdatedAt      *Time   `json:"updatedAt"`
 }
 
 // DeleteCustomModelResponse represents the response when deleting a custom model.
 type DeleteCustomModelResponse struct {
-	DeleteCustomModelsByPK struct {
-		ID *string `json:"id"`
-	} `json:"delete_custom_models_by_pk"`
+	DeleteCustomModelsByPK ResourceID `json:"delete_custom_models_by_pk"`
 }
 
 // ListPlatformModelsResponse represents the response when listing platform models.
 type ListPlatformModelsResponse struct {
-	CustomModels []struct {
-		AKUUID        *string `json:"akUUID"`
-		BaseModel     *string `json:"baseModel"`
-		CreatorName   *string `json:"creatorName"`
-		Description   *string `json:"description"`
-		ID            *string `json:"id"`
-		Name          *string `json:"name"`
-		URLImage      *string `json:"urlImage"`
-		WeightDefault *int    `json:"weightDefault"`
-		WeightMax     *int    `json:"weightMax"`
-		WeightMin     *int    `json:"weightMin"`
-	} `json:"custom_models"`
+	CustomModels []PlatformModel `json:"custom_models"`
+}
+
+// PlatformModel represents a model provided by the platform.
+type PlatformModel struct {
+	AKUUID        *string `json:"akUUID"`
+	BaseModel     *string `json:"baseModel"`
+	CreatorName   *string `json:"creatorName"`
+	Description   *string `json:"description"`
+	ID            *string `json:"id"`
+	Name          *string `json:"name"`
+	URLImage      *string `json:"urlImage"`
+	WeightDefault *int    `json:"weightDefault"`
+	WeightMax     *int    `json:"weightMax"`
+	WeightMin     *int    `json:"weightMin"`
 }
 
 // PaginationParams defines parameters for paginated requests.
@@ -435,22 +649,28 @@ type UpdateCustomModelRequest struct {
 
 // UpdateCustomModelResponse represents the response after updating a custom model.
 type UpdateCustomModelResponse struct {
-	UpdatedCustomModelsByPk struct {
-		ID          *string `json:"id"`
-		Name        *string `json:"name"`
-		Description *string `json:"description"`
-		// Add other fields as necessary
-	} `json:"updated_custom_models_by_pk"`
+	UpdatedCustomModelsByPk UpdatedCustomModel `json:"updated_custom_models_by_pk"`
+}
+
+// UpdatedCustomModel represents the updated fields of a custom model.
+type UpdatedCustomModel struct {
+	ID          *string `json:"id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	// Add other fields as necessary
 }
 
 // Prompt-related types
 
 // GenerateRandomPromptResponse represents the response from generating a random prompt.
 type GenerateRandomPromptResponse struct {
-	PromptGeneration *struct {
-		APICreditCost *int    `json:"apiCreditCost"`
-		Prompt        *string `json:"prompt"`
-	} `json:"promptGeneration"`
+	PromptGeneration *PromptGeneration `json:"promptGeneration"`
+}
+
+// PromptGeneration represents a prompt generated or improved by the API.
+type PromptGeneration struct {
+	APICreditCost *int    `json:"apiCreditCost"`
+	Prompt        *string `json:"prompt"`
 }
 
 // ImprovePromptRequest represents the payload for improving a prompt.
@@ -460,25 +680,123 @@ type ImprovePromptRequest struct {
 
 // ImprovePromptResponse represents the response from improving a prompt.
 type ImprovePromptResponse struct {
-	PromptGeneration *struct {
-		APICreditCost *int    `json:"apiCreditCost"`
-		Prompt        *string `json:"prompt"`
-	} `json:"promptGeneration"`
+	PromptGeneration *PromptGeneration `json:"promptGeneration"`
 }
 
 // Pricing Calculator-related types
 
 // CalculateAPICostRequest represents the payload for calculating API cost.
 type CalculateAPICostRequest struct {
-	Service       string                 `json:"service"`
-	ServiceParams map[string]interface{} `json:"serviceParams,omitempty"`
+	Service       PricingServiceType    `json:"service"`
+	ServiceParams *PricingServiceParams `json:"serviceParams,omitempty"`
+}
+
+// PricingServiceType identifies a priceable service of the pricing calculator.
+type PricingServiceType string
+
+const (
+	PricingServiceTypeImageGeneration         PricingServiceType = "IMAGE_GENERATION"
+	PricingServiceTypeFantasyAvatarGeneration PricingServiceType = "FANTASY_AVATAR_GENERATION"
+	PricingServiceTypeLCMGeneration           PricingServiceType = "LCM_GENERATION"
+	PricingServiceTypeModelTraining           PricingServiceType = "MODEL_TRAINING" // fine-tuned model training
+	PricingServiceTypeMotionGeneration        PricingServiceType = "MOTION_GENERATION"
+	PricingServiceTypeTextureGeneration       PricingServiceType = "TEXTURE_GENERATION"
+	PricingServiceTypeUniversalUpscaler       PricingServiceType = "UNIVERSAL_UPSCALER"
+	PricingServiceTypeUniversalUpscalerUltra  PricingServiceType = "UNIVERSAL_UPSCALER_ULTRA"
+)
+
+// PricingServiceParams holds the parameters for the service being priced.
+// Only the field matching CalculateAPICostRequest.Service needs to be set.
+type PricingServiceParams struct {
+	ImageGeneration         *ImageGenerationPricingParams         `json:"IMAGE_GENERATION,omitempty"`
+	FantasyAvatarGeneration *FantasyAvatarGenerationPricingParams `json:"FANTASY_AVATAR_GENERATION,omitempty"`
+	LCMGeneration           *LCMGenerationPricingParams           `json:"LCM_GENERATION,omitempty"`
+	ModelTraining           *ModelTrainingPricingParams           `json:"MODEL_TRAINING,omitempty"`
+	MotionGeneration        *MotionGenerationPricingParams        `json:"MOTION_GENERATION,omitempty"`
+	TextureGeneration       *TextureGenerationPricingParams       `json:"TEXTURE_GENERATION,omitempty"`
+	UniversalUpscaler       *UniversalUpscalerPricingParams       `json:"UNIVERSAL_UPSCALER,omitempty"`
+	UniversalUpscalerUltra  *UniversalUpscalerUltraPricingParams  `json:"UNIVERSAL_UPSCALER_ULTRA,omitempty"`
+}
+
+// ImageGenerationPricingParams represents the pricing parameters of an image generation.
+type ImageGenerationPricingParams struct {
+	AlchemyMode         *bool               `json:"alchemyMode,omitempty"`
+	ControlnetsCost     *int                `json:"controlnetsCost,omitempty"`
+	HighResolution      *bool               `json:"highResolution,omitempty"`
+	ImageHeight         *int                `json:"imageHeight,omitempty"`
+	ImageWidth          *int                `json:"imageWidth,omitempty"`
+	InferenceSteps      *int                `json:"inferenceSteps,omitempty"`
+	IsModelCustom       *bool               `json:"isModelCustom,omitempty"`
+	IsPhoenix           *bool               `json:"isPhoenix,omitempty"`
+	IsSDXL              *bool               `json:"isSDXL,omitempty"`
+	LoraCount           *int                `json:"loraCount,omitempty"`
+	NumImages           *int                `json:"numImages,omitempty"`
+	PromptMagic         *bool               `json:"promptMagic,omitempty"`
+	PromptMagicStrength *float64            `json:"promptMagicStrength,omitempty"`
+	PromptMagicVersion  *PromptMagicVersion `json:"promptMagicVersion,omitempty"`
+	Ultra               *bool               `json:"ultra,omitempty"`
+}
+
+// FantasyAvatarGenerationPricingParams represents the pricing parameters of a fantasy avatar generation.
+type FantasyAvatarGenerationPricingParams struct {
+	ImageHeight *int `json:"imageHeight,omitempty"`
+	ImageWidth  *int `json:"imageWidth,omitempty"`
+	NumImages   *int `json:"numImages,omitempty"`
+}
+
+// LCMGenerationPricingParams represents the pricing parameters of a realtime canvas (LCM) generation.
+type LCMGenerationPricingParams struct {
+	Height         *int  `json:"height,omitempty"`
+	InstantRefine  *bool `json:"instantRefine,omitempty"`
+	Inpaint        *bool `json:"inpaint,omitempty"`
+	RefineCreative *bool `json:"refineCreative,omitempty"`
+	Width          *int  `json:"width,omitempty"`
+}
+
+// ModelTrainingPricingParams represents the pricing parameters of a fine-tuned model training.
+type ModelTrainingPricingParams struct {
+	Resolution *int              `json:"resolution,omitempty"`
+	SDVersion  *SDVersion        `json:"sdVersion,omitempty"`
+	Strength   *TrainingStrength `json:"strength,omitempty"`
+}
+
+// MotionGenerationPricingParams represents the pricing parameters of a motion generation.
+type MotionGenerationPricingParams struct {
+	DurationSeconds *int `json:"durationSeconds,omitempty"`
+}
+
+// TextureGenerationPricingParams represents the pricing parameters of a texture generation.
+type TextureGenerationPricingParams struct {
+	Preview *bool `json:"preview,omitempty"`
+}
+
+// UniversalUpscalerPricingParams represents the pricing parameters of the universal upscaler.
+type UniversalUpscalerPricingParams struct {
+	InputHeight       *int `json:"inputHeight,omitempty"`
+	InputWidth        *int `json:"inputWidth,omitempty"`
+	UpscaleMultiplier *int `json:"upscaleMultiplier,omitempty"`
+}
+
+// UniversalUpscalerUltraPricingParams represents the pricing parameters of the universal upscaler ultra.
+type UniversalUpscalerUltraPricingParams struct {
+	InputHeight       *int     `json:"inputHeight,omitempty"`
+	InputWidth        *int     `json:"inputWidth,omitempty"`
+	UpscaleMultiplier *float64 `json:"upscaleMultiplier,omitempty"`
 }
 
 // CalculateAPICostResponse represents the response from calculating API cost.
 type CalculateAPICostResponse struct {
-	CalculateProductionApiServiceCost struct {
-		Cost *int `json:"cost"`
-	} `json:"calculateProductionApiServiceCost"`
+	CalculateProductionApiServiceCost ServiceCost `json:"calculateProductionApiServiceCost"`
+}
+
+// ServiceCost represents the calculated cost of a service.
+type ServiceCost struct {
+	Cost *int `json:"cost"`
+}
+
+// Cost returns the calculated API credit cost, or 0 if absent.
+func (r *CalculateAPICostResponse) Cost() int {
+	return deref(r.CalculateProductionApiServiceCost.Cost)
 }
 
 // Realtime Canvas-related types
@@ -568,36 +886,52 @@ type PerformAlchemyUpscaleRequest struct {
 
 // PerformAlchemyUpscaleResponse represents the response after performing Alchemy Upscale.
 type PerformAlchemyUpscaleResponse struct {
-	LCMGenerationJob *struct {
-		APICreditCost    *int     `json:"apiCreditCost"`
-		GeneratedImageID *string  `json:"generatedImageId"`
-		GenerationID     []string `json:"generationId"`
-		ImageDataURL     []string `json:"imageDataUrl"`
-		RequestTimestamp *string  `json:"requestTimestamp"`
-		VariationID      []string `json:"variationId"`
-	} `json:"lcmGenerationJob"`
+	LCMGenerationJob *AlchemyUpscaleJob `json:"lcmGenerationJob"`
+}
+
+// AlchemyUpscaleJob represents the result of an Alchemy Upscale.
+type AlchemyUpscaleJob struct {
+	APICreditCost    *int     `json:"apiCreditCost"`
+	GeneratedImageID *string  `json:"generatedImageId"`
+	GenerationID     []string `json:"generationId"`
+	ImageDataURL     []string `json:"imageDataUrl"`
+	RequestTimestamp *string  `json:"requestTimestamp"`
+	VariationID      []string `json:"variationId"`
 }
 
 // Texture-related types
 
 // CreateTextureGenerationRequest represents the payload for creating a texture generation.
 type CreateTextureGenerationRequest struct {
-	FrontRotationOffset *int    `json:"front_rotation_offset,omitempty"`
-	ModelAssetID        *string `json:"modelAssetId,omitempty"`
-	NegativePrompt      *string `json:"negative_prompt,omitempty"`
-	Preview             *bool   `json:"preview,omitempty"`
-	PreviewDirection    *string `json:"preview_direction,omitempty"`
-	Prompt              *string `json:"prompt,omitempty"`
-	SDVersion           *string `json:"sd_version,omitempty"`
-	Seed                *int    `json:"seed,omitempty"`
+	FrontRotationOffset *int              `json:"front_rotation_offset,omitempty"`
+	ModelAssetID        *string           `json:"modelAssetId,omitempty"`
+	NegativePrompt      *string           `json:"negative_prompt,omitempty"`
+	Preview             *bool             `json:"preview,omitempty"`
+	PreviewDirection    *PreviewDirection `json:"preview_direction,omitempty"`
+	Prompt              *string           `json:"prompt,omitempty"`
+	SDVersion           *SDVersion        `json:"sd_version,omitempty"`
+	Seed                *int              `json:"seed,omitempty"`
 }
 
+// PreviewDirection is the side of a 3D model shown in a texture preview.
+type PreviewDirection string
+
+const (
+	PreviewDirectionFront PreviewDirection = "front"
+	PreviewDirectionBack  PreviewDirection = "back"
+	PreviewDirectionLeft  PreviewDirection = "left"
+	PreviewDirectionRight PreviewDirection = "right"
+)
+
 // CreateTextureGenerationResponse represents the response after creating a texture generation.
 type CreateTextureGenerationResponse struct {
-	TextureGenerationJob struct {
-		APICreditCost *int    `json:"apiCreditCost"`
-		ID            *string `json:"id"`
-	} `json:"textureGenerationJob"`
+	TextureGenerationJob TextureGenerationJob `json:"textureGenerationJob"`
+}
+
+// TextureGenerationJob represents a submitted texture generation job.
+type TextureGenerationJob struct {
+	APICreditCost *int    `json:"apiCreditCost"`
+	ID            *string `json:"id"`
 }
 
 // ThreeD Model Assets-related types
@@ -610,44 +944,51 @@ type Upload3DModelRequest struct {
 
 // Upload3DModelResponse represents the response after uploading a 3D model.
 type Upload3DModelResponse struct {
-	UploadModelAsset *struct {
-		ModelFields *string `json:"modelFields"`
-		ModelID     *string `json:"modelId"`
-		ModelKey    *string `json:"modelKey"`
-		ModelURL    *string `json:"modelUrl"`
-	} `json:"uploadModelAsset"`
+	UploadModelAsset *ModelAssetUpload `json:"uploadModelAsset"`
+}
+
+// ModelAssetUpload holds the presigned S3 details for uploading a 3D model.
+type ModelAssetUpload struct {
+	ModelFields *string `json:"modelFields"`
+	ModelID     *string `json:"modelId"`
+	ModelKey    *string `json:"modelKey"`
+	ModelURL    *string `json:"modelUrl"`
 }
 
 // Get3DModelsByUserResponse represents the response when retrieving 3D models by user ID.
 type Get3DModelsByUserResponse struct {
-	ModelAssets []struct {
-		CreatedAt *Time   `json:"createdAt"`
-		ID        *string `json:"id"`
-		MeshURL   *string `json:"meshUrl"`
-		Name      *string `json:"name"`
-		UpdatedAt *Time   `json:"updatedAt"`
-		UserID    *string `json:"userId"`
-	} `json:"model_assets"`
+	ModelAssets []ModelAssetSummary `json:"model_assets"`
+}
+
+// ModelAssetSummary represents a 3D model asset as listed by user.
+type ModelAssetSummary struct {
+	CreatedAt *Time   `json:"createdAt"`
+	ID        *string `json:"id"`
+	MeshURL   *string `json:"meshUrl"`
+	Name      *string `json:"name"`
+	UpdatedAt *Time   `json:"updatedAt"`
+	UserID    *string `json:"userId"`
 }
 
 // Get3DModelByIDResponse represents the response when retrieving a 3D model by ID.
 type Get3DModelByIDResponse struct {
-	ModelAssetsByPK struct {
-		CreatedAt   *Time   `json:"createdAt"`
-		Description *string `json:"description"`
-		ID          *string `json:"id"`
-		MeshURL     *string `json:"meshUrl"`
-		Name        *string `json:"name"`
-		UpdatedAt   *Time   `json:"updatedAt"`
-		UserID      *string `json:"userId"`
-	} `json:"model_assets_by_pk"`
+	ModelAssetsByPK ModelAsset `json:"model_assets_by_pk"`
+}
+
+// ModelAsset represents a 3D model asset.
+type ModelAsset struct {
+	CreatedAt   *Time   `json:"createdAt"`
+	Description *string `json:"description"`
+	ID          *string `json:"id"`
+	MeshURL     *string `json:"meshUrl"`
+	Name        *string `json:"name"`
+	UpdatedAt   *Time   `json:"updatedAt"`
+	UserID      *string `json:"userId"`
 }
 
 // Delete3DModelResponse represents the response when deleting a 3D model.
 type Delete3DModelResponse struct {
-	DeleteModelAssetsByPK struct {
-		ID *string `json:"id"`
-	} `json:"delete_model_assets_by_pk"`
+	DeleteModelAssetsByPK ResourceID `json:"delete_model_assets_by_pk"`
 }
 
 // Variation-related types
@@ -666,13 +1007,24 @@ type UniversalUpscalerRequest struct {
 
 // GetVariationResponse represents the response when retrieving variation details.
 type GetVariationResponse struct {
-	GeneratedImageVariationGeneric []struct {
-		CreatedAt     *Time   `json:"createdAt"`
-		ID            *string `json:"id"`
-		Status        *string `json:"status"`
-		TransformType *string `json:"transformType"`
-		URL           *string `json:"url"`
-	} `json:"generated_image_variation_generic"`
+	GeneratedImageVariationGeneric []Variation `json:"generated_image_variation_generic"`
+}
+
+// Variation returns the retrieved variation, or nil if absent.
+func (r *GetVariationResponse) Variation() *Variation {
+	if len(r.GeneratedImageVariationGeneric) == 0 {
+		return nil
+	}
+	return &r.GeneratedImageVariationGeneric[0]
+}
+
+// Variation represents a variation retrieved by ID.
+type Variation struct {
+	CreatedAt     *Time             `json:"createdAt"`
+	ID            *string           `json:"id"`
+	Status        *GenerationStatus `json:"status"`
+	TransformType *TransformType    `json:"transformType"`
+	URL           *string           `json:"url"`
 }
 
 // UpscaleVariationRequest represents the payload for creating an upscale variation.
@@ -683,10 +1035,7 @@ type UpscaleVariationRequest struct {
 
 // UpscaleVariationResponse represents the response from creating an upscale variation.
 type UpscaleVariationResponse struct {
-	SdUpscaleJob struct {
-		ID            *string `json:"id"`
-		APICreditCost *int    `json:"apiCreditCost"`
-	} `json:"sdUpscaleJob"`
+	SdUpscaleJob VariationJob `json:"sdUpscaleJob"`
 }
 
 // VariationJob represents variation job details.
@@ -716,20 +1065,27 @@ type UploadInitImageRequest struct {
 	ImageFile string `json:"image_file"`
 }
 
+// UploadInitImageResponse represents the response from uploading an init image.
+type UploadInitImageResponse struct {
+	UploadInitImageID *string `json:"uploadInitImageId"`
+	Message           string  `json:"message"`
+}
+
 // GetSingleInitImageResponse represents the response from retrieving a single init image.
 type GetSingleInitImageResponse struct {
-	InitImagesByPk struct {
-		CreatedAt *Time   `json:"createdAt"`
-		ID        *string `json:"id"`
-		URL       *string `json:"url"`
-	} `json:"init_images_by_pk"`
+	InitImagesByPk InitImage `json:"init_images_by_pk"`
+}
+
+// InitImage represents an uploaded init image.
+type InitImage struct {
+	CreatedAt *Time   `json:"createdAt"`
+	ID        *string `json:"id"`
+	URL       *string `json:"url"`
 }
 
 // DeleteInitImageResponse represents the response from deleting an init image.
 type DeleteInitImageResponse struct {
-	DeleteInitImagesByPk struct {
-		ID *string `json:"id"`
-	} `json:"delete_init_images_by_pk"`
+	DeleteInitImagesByPk ResourceID `json:"delete_init_images_by_pk"`
 }
 
 // UploadCanvasInitAndMaskImageRequest represents the payload for uploading canvas init and mask images.
@@ -740,16 +1096,19 @@ type UploadCanvasInitAndMaskImageRequest struct {
 
 // UploadCanvasInitAndMaskImageResponse represents the response from uploading canvas init and mask images.
 type UploadCanvasInitAndMaskImageResponse struct {
-	UploadCanvasInitImage *struct {
-		InitFields  string `json:"initFields"`
-		InitImageID string `json:"initImageId"`
-		InitKey     string `json:"initKey"`
-		InitURL     string `json:"initUrl"`
-		MaskFields  string `json:"maskFields"`
-		MaskImageID string `json:"maskImageId"`
-		MaskKey     string `json:"maskKey"`
-		MaskURL     string `json:"maskUrl"`
-	} `json:"uploadCanvasInitImage"`
+	UploadCanvasInitImage *CanvasInitImageUpload `json:"uploadCanvasInitImage"`
+}
+
+// CanvasInitImageUpload holds the presigned S3 details for uploading canvas init and mask images.
+type CanvasInitImageUpload struct {
+	InitFields  string `json:"initFields"`
+	InitImageID string `json:"initImageId"`
+	InitKey     string `json:"initKey"`
+	InitURL     string `json:"initUrl"`
+	MaskFields  string `json:"maskFields"`
+	MaskImageID string `json:"maskImageId"`
+	MaskKey     string `json:"maskKey"`
+	MaskURL     string `json:"maskUrl"`
 }
 
 // Motion-related types
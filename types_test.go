@@ -0,0 +1,116 @@
+package leonardo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestResponseAccessors tests the convenience accessors on response wrappers.
+func TestResponseAccessors(t *testing.T) {
+	data := `{
+		"generations_by_pk": {
+			"id": "gen-123",
+			"status": "COMPLETE",
+			"generation_elements": [{"id": "el-1", "weightApplied": 1}],
+			"generated_images": [{
+				"id": "img-1",
+				"url": "https://cdn.leonardo.ai/img-1.png",
+				"generated_image_variation_generics": [{"id": "var-1", "status": "COMPLETE", "transformType": "UPSCALE"}]
+			}]
+		}
+	}`
+
+	var gen GetGenerationResponse
+	if err := json.Unmarshal([]byte(data), &gen); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if gen.Status() != GenerationStatusComplete {
+		t.Errorf("Expected status COMPLETE, got %q", gen.Status())
+	}
+	images := gen.Images()
+	if len(images) != 1 || *images[0].ID != "img-1" {
+		t.Fatalf("Expected one image 'img-1', got %+v", images)
+	}
+	if v := images[0].GeneratedImageVariationGenerics; len(v) != 1 || *v[0].ID != "var-1" {
+		t.Errorf("Expected variation 'var-1', got %+v", v)
+	}
+	if e := gen.GenerationsByPK.GenerationElements; len(e) != 1 || *e[0].ID != "el-1" {
+		t.Errorf("Expected element 'el-1', got %+v", e)
+	}
+
+	create := CreateGenerationResponse{SDGenerationJob: GenerationJob{GenerationID: Ptr("gen-123"), APICreditCost: Ptr(8)}}
+	if create.GenerationID() != "gen-123" || create.APICreditCost() != 8 {
+		t.Errorf("Unexpected accessors: %q %d", create.GenerationID(), create.APICreditCost())
+	}
+
+	var user GetUserInfoResponse
+	if user.Details() != nil {
+		t.Error("Expected nil details for an empty response")
+	}
+	user.UserDetails = []UserDetails{{APIPaidTokens: Ptr(100), APISubscriptionTokens: Ptr(50)}}
+	if d := user.Details(); d == nil || d.APITokens() != 150 {
+		t.Errorf("Expected 150 API tokens, got %+v", d)
+	}
+
+	var variation GetVariationResponse
+	if variation.Variation() != nil {
+		t.Error("Expected nil variation for an empty response")
+	}
+}
+
+// TestTime tests parsing and round-tripping the timestamp formats the API sends.
+func TestTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{`"2024-05-01T12:30:45.123"`, time.Date(2024, 5, 1, 12, 30, 45, 123e6, time.UTC)},
+		{`"2024-05-01T12:30:45"`, time.Date(2024, 5, 1, 12, 30, 45, 0, time.UTC)},
+		{`"2024-05-01T12:30:45.123456"`, time.Date(2024, 5, 1, 12, 30, 45, 123456e3, time.UTC)},
+		{`"2024-05-01T12:30:45.123Z"`, time.Date(2024, 5, 1, 12, 30, 45, 123e6, time.UTC)},
+		{`"2024-05-01T14:30:45+02:00"`, time.Date(2024, 5, 1, 12, 30, 45, 0, time.UTC)},
+		{`"2024-05-01T12:30:45.123+00:00"`, time.Date(2024, 5, 1, 12, 30, 45, 123e6, time.UTC)},
+		{`"2024-05-01 12:30:45.123+0000"`, time.Date(2024, 5, 1, 12, 30, 45, 123e6, time.UTC)},
+		{`"2024-05-01"`, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		var got Time
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) failed: %v", tt.in, err)
+			continue
+		}
+		if !got.Time.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got.Time, tt.want)
+		}
+		out, _ := json.Marshal(got)
+		if string(out) != tt.in {
+			t.Errorf("Marshal after Unmarshal(%s) = %s, want the original", tt.in, out)
+		}
+	}
+
+	for _, in := range []string{`null`, `""`} {
+		var got Time
+		if err := json.Unmarshal([]byte(in), &got); err != nil || !got.IsZero() {
+			t.Errorf("Unmarshal(%s) = %v, %v; want zero time", in, got, err)
+		}
+		if out, _ := json.Marshal(got); string(out) != in {
+			t.Errorf("Marshal after Unmarshal(%s) = %s, want the original", in, out)
+		}
+	}
+
+	var bad Time
+	if err := json.Unmarshal([]byte(`"yesterday"`), &bad); err == nil {
+		t.Error("Expected error for unrecognized format, got nil")
+	}
+
+	// A changed time marshals in the default format rather than the original text.
+	changed, _ := ParseTime("2024-05-01T12:30:45Z")
+	changed.Time = changed.Time.Add(time.Hour)
+	if out, _ := json.Marshal(changed); string(out) != `"2024-05-01T13:30:45.000"` {
+		t.Errorf("Expected changed time in the default format, got %s", out)
+	}
+	if out, _ := json.Marshal(Time{}); string(out) != "null" {
+		t.Errorf("Expected zero time to marshal as null, got %s", out)
+	}
+}
@@ -21,12 +21,13 @@ func (s *UserService) GetUserInfo(ctx context.Context) (*GetUserInfoResponse, er
 	var resp GetUserInfoResponse
 	path := "/me"
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating GetUserInfo request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "User",
+		Operation: "GetUserInfo",
+		Method:    "GET",
+		Path:      path,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving user info failed: %w", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // TestGetUserInfo tests the GetUserInfo method.
@@ -20,20 +21,9 @@ func TestGetUserInfo(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := GetUserInfoResponse{
-			UserDetails: []struct {
-				APIPlanTokenRenewalDate *string `json:"apiPlanTokenRenewalDate"`
-				APIConcurrencySlots     *int    `json:"apiConcurrencySlots"`
-				APIPaidTokens           *int    `json:"apiPaidTokens"`
-				APISubscriptionTokens   *int    `json:"apiSubscriptionTokens"`
-				PaidTokens              *int    `json:"paidTokens"`
-				SubscriptionGPTTokens   *int    `json:"subscriptionGptTokens"`
-				SubscriptionModelTokens *int    `json:"subscriptionModelTokens"`
-				SubscriptionTokens      *int    `json:"subscriptionTokens"`
-				TokenRenewalDate        *string `json:"tokenRenewalDate"`
-				User                    User    `json:"user"`
-			}{
+			UserDetails: []UserDetails{
 				{
-					APIPlanTokenRenewalDate: Ptr("2023-11-01T00:00:00Z"),
+					APIPlanTokenRenewalDate: &Time{Time: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)},
 					APIConcurrencySlots:     Ptr(5),
 					APIPaidTokens:           Ptr(200),
 					APISubscriptionTokens:   Ptr(300),
@@ -41,7 +31,7 @@ func TestGetUserInfo(t *testing.T) {
 					SubscriptionGPTTokens:   Ptr(250),
 					SubscriptionModelTokens: Ptr(100),
 					SubscriptionTokens:      Ptr(400),
-					TokenRenewalDate:        Ptr("2023-10-15T00:00:00Z"),
+					TokenRenewalDate:        &Time{Time: time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)},
 					User: User{
 						ID:       Ptr("user-123"),
 						Username: Ptr("testuser"),
@@ -86,8 +76,8 @@ func TestGetUserInfo(t *testing.T) {
 	if *userDetail.APIPaidTokens != 200 {
 		t.Errorf("Expected APICredits 200, got %d", *userDetail.APIPaidTokens)
 	}
-	if *userDetail.APIPlanTokenRenewalDate != "2023-11-01T00:00:00Z" {
-		t.Errorf("Expected APIPlanTokenRenewalDate '2023-11-01T00:00:00Z', got '%s'", *userDetail.APIPlanTokenRenewalDate)
+	if userDetail.APIPlanTokenRenewalDate.Time.Format(time.RFC3339) != "2023-11-01T00:00:00Z" {
+		t.Errorf("Expected APIPlanTokenRenewalDate '2023-11-01T00:00:00Z', got '%s'", userDetail.APIPlanTokenRenewalDate)
 	}
 	if *userDetail.APIConcurrencySlots != 5 {
 		t.Errorf("Expected APIConcurrencySlots 5, got %d", *userDetail.APIConcurrencySlots)
@@ -0,0 +1,150 @@
+package leonardo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validate checks r against the limits the API documents, so mistakes are caught
+// before credits are spent. All problems found are returned, joined with errors.Join.
+func (r CreateGenerationRequest) Validate() error {
+	model := r.model()
+	_, known := ModelByID(model.ID)
+	return r.validate(model, known)
+}
+
+// validate checks r as a request for model. known reports whether the model's
+// capabilities are known rather than inferred from its base model.
+func (r CreateGenerationRequest) validate(model ModelInfo, known bool) error {
+	var errs []error
+	if strings.TrimSpace(r.Prompt) == "" {
+		errs = append(errs, errors.New("prompt is required"))
+	}
+
+	width, height := r.size()
+	errs = append(errs, checkDimensions(width, height, model, deref(r.HighResolution), deref(r.Ultra), r.UpscaleRatio)...)
+
+	errs = append(errs, checkRange("guidance_scale", r.GuidanceScale, 1, 20)...)
+	errs = append(errs, checkRange("num_inference_steps", r.NumInferenceSteps, 10, 60)...)
+	errs = append(errs, checkRange("num_images", r.NumImages, 1, 8)...)
+	errs = append(errs, checkRange("contrastRatio", r.ContrastRatio, 0.1, 1.0)...)
+	errs = append(errs, checkRange("promptMagicStrength", r.PromptMagicStrength, 0.1, 1.0)...)
+	errs = append(errs, checkRange("init_strength", r.InitStrength, 0.1, 0.9)...)
+
+	errs = append(errs, checkEnum("presetStyle", r.PresetStyle)...)
+	errs = append(errs, checkEnum("scheduler", r.Scheduler)...)
+	errs = append(errs, checkEnum("sd_version", r.SDVersion)...)
+	errs = append(errs, checkEnum("photoRealVersion", r.PhotoRealVersion)...)
+	errs = append(errs, checkEnum("promptMagicVersion", r.PromptMagicVersion)...)
+	errs = append(errs, checkEnum("transparency", r.Transparency)...)
+	errs = append(errs, checkEnum("canvasRequestType", r.CanvasRequestType)...)
+
+	errs = append(errs, r.checkModes(model, known)...)
+	return errors.Join(errs...)
+}
+
+// checkModes returns the problems with the combination of modes in r.
+func (r CreateGenerationRequest) checkModes(model ModelInfo, known bool) []error {
+	var errs []error
+	alchemy := r.Alchemy == nil || *r.Alchemy // the API enables Alchemy by default
+	photoReal := deref(r.PhotoReal)
+
+	if deref(r.Ultra) && alchemy {
+		errs = append(errs, errors.New("ultra requires alchemy to be disabled"))
+	}
+
+	if photoReal {
+		if !alchemy {
+			errs = append(errs, errors.New("photoReal requires alchemy"))
+		}
+		if deref(r.PhotoRealVersion) == PhotoRealVersionV2 {
+			if r.ModelID == nil {
+				errs = append(errs, errors.New("photoReal v2 requires a modelId"))
+			} else if known && !model.PhotoReal {
+				errs = append(errs, fmt.Errorf("model %s does not support photoReal v2", modelLabel(model)))
+			}
+		} else if r.ModelID != nil {
+			errs = append(errs, errors.New("photoReal v1 cannot be used with a modelId"))
+		}
+	}
+	if r.PhotoRealStrength != nil {
+		if !photoReal {
+			errs = append(errs, errors.New("photoRealStrength requires photoReal"))
+		}
+		if s := *r.PhotoRealStrength; s != 0.45 && s != 0.5 && s != 0.55 {
+			errs = append(errs, fmt.Errorf("photoRealStrength %v must be 0.45, 0.5 or 0.55", s))
+		}
+	}
+
+	if r.PresetStyle != nil {
+		switch style := *r.PresetStyle; {
+		case style == PresetStyleLeonardo && alchemy:
+			errs = append(errs, fmt.Errorf("presetStyle %s requires alchemy to be disabled", style))
+		case photoRealPresetStyles[style] && !photoReal:
+			errs = append(errs, fmt.Errorf("presetStyle %s requires photoReal", style))
+		case alchemyPresetStyles[style] && !alchemy:
+			errs = append(errs, fmt.Errorf("presetStyle %s requires alchemy", style))
+		}
+	}
+
+	if deref(r.Unzoom) {
+		if r.UnzoomAmount == nil {
+			errs = append(errs, errors.New("unzoom requires unzoomAmount"))
+		}
+		if r.InitImageID == nil {
+			errs = append(errs, errors.New("unzoom requires init_image_id"))
+		}
+	}
+
+	if deref(r.CanvasRequest) {
+		if r.CanvasRequestType == nil {
+			errs = append(errs, errors.New("canvasRequest requires canvasRequestType"))
+		}
+		if r.CanvasInitID == nil {
+			errs = append(errs, errors.New("canvasRequest requires canvasInitId"))
+		}
+		if deref(r.CanvasRequestType) == CanvasRequestTypeInpaint && r.CanvasMaskID == nil {
+			errs = append(errs, errors.New("inpainting requires canvasMaskId"))
+		}
+	} else if r.CanvasRequestType != nil || r.CanvasInitID != nil || r.CanvasMaskID != nil {
+		errs = append(errs, errors.New("canvas fields require canvasRequest"))
+	}
+
+	return errs
+}
+
+// Preset styles that are only available with Alchemy or PhotoReal.
+var (
+	alchemyPresetStyles = map[PresetStyle]bool{
+		PresetStyleAnime: true, PresetStyleCreative: true, PresetStyleDynamic: true, PresetStyleEnvironment: true,
+		PresetStyleGeneral: true, PresetStyleIllustration: true, PresetStylePhotography: true, PresetStyleRaytraced: true,
+		PresetStyleRender3D: true, PresetStyleSketchBW: true, PresetStyleSketchColor: true,
+	}
+	photoRealPresetStyles = map[PresetStyle]bool{
+		PresetStyleStockPhoto: true, PresetStyleVibrant: true, PresetStyleUnprocessed: true, PresetStyleBokeh: true,
+		PresetStyleCinematic: true, PresetStyleCinematicCloseup: true, PresetStyleFashion: true, PresetStyleFilm: true,
+		PresetStyleFood: true, PresetStyleHDR: true, PresetStyleLongExposure: true, PresetStyleMacro: true,
+		PresetStyleMinimalistic: true, PresetStyleMonochrome: true, PresetStyleMoody: true, PresetStyleNeutral: true,
+		PresetStylePortrait: true, PresetStyleRetro: true,
+	}
+)
+
+// checkRange returns an error if v is set and outside [min, max].
+func checkRange[T int | float64](name string, v *T, min, max T) []error {
+	if v != nil && (*v < min || *v > max) {
+		return []error{fmt.Errorf("%s %v must be between %v and %v", name, *v, min, max)}
+	}
+	return nil
+}
+
+// checkEnum returns an error if v is set and not a known value.
+func checkEnum[T interface {
+	~string
+	IsValid() bool
+}](name string, v *T) []error {
+	if v != nil && !(*v).IsValid() {
+		return []error{fmt.Errorf("%s %q is not a known value", name, string(*v))}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // VariationService provides methods to interact with the Variation endpoints.
@@ -23,12 +24,15 @@ func (s *VariationService) CreateUnzoomVariation(ctx context.Context, req Variat
 	var resp CreateUnzoomVariationResponse
 	path := "/variations/unzoom"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateUnzoomVariation request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Variation",
+		Operation: "CreateUnzoomVariation",
+		Method:    http.MethodPost,
+		Path:      path,
+		ID:        req.ID,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating unzoom variation failed: %w", err)
 	}
@@ -45,12 +49,15 @@ func (s *VariationService) CreateUpscaleVariation(ctx context.Context, id string
 	var resp UpscaleVariationResponse
 	path := "/variations/upscale"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateUpscaleVariation request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Variation",
+		Operation: "CreateUpscaleVariation",
+		Method:    "POST",
+		Path:      path,
+		ID:        id,
+		Request:   reqBody,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating upscale variation failed: %w", err)
 	}
@@ -64,12 +71,15 @@ func (s *VariationService) CreateNoBackgroundVariation(ctx context.Context, req
 	var resp CreateNoBackgroundVariationResponse
 	path := "/variations/nobg"
 
-	httpReq, err := s.client.NewRequest(ctx, http.MethodPost, path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateNoBackgroundVariation request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Variation",
+		Operation: "CreateNoBackgroundVariation",
+		Method:    http.MethodPost,
+		Path:      path,
+		ID:        req.ID,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating no background variation failed: %w", err)
 	}
@@ -83,12 +93,14 @@ func (s *VariationService) CreateUniversalUpscalerVariation(ctx context.Context,
 	var resp UniversalUpscalerResponse
 	path := "/variations/universal-upscaler"
 
-	httpReq, err := s.client.NewRequest(ctx, "POST", path, req)
-	if err != nil {
-		return nil, fmt.Errorf("creating CreateUniversalUpscalerVariation request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Variation",
+		Operation: "CreateUniversalUpscalerVariation",
+		Method:    "POST",
+		Path:      path,
+		Request:   req,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("creating universal upscaler variation failed: %w", err)
 	}
@@ -102,15 +114,57 @@ func (s *VariationService) GetVariation(ctx context.Context, id string) (*GetVar
 	var resp GetVariationResponse
 	path := fmt.Sprintf("/variations/%s", url.PathEscape(id))
 
-	httpReq, err := s.client.NewRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating GetVariation request failed: %w", err)
-	}
-
-	err = s.client.Do(httpReq, &resp)
+	err := s.client.invoke(ctx, &Call{
+		Service:   "Variation",
+		Operation: "GetVariation",
+		Method:    "GET",
+		Path:      path,
+		ID:        id,
+		Response:  &resp,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("retrieving variation failed: %w", err)
 	}
 
 	return &resp, nil
 }
+
+// WaitForVariation polls GetVariation until the variation is no longer PENDING
+// or ctx is done, and returns the last response. An interval of zero uses
+// DefaultPollInterval. A FAILED variation is returned with an error.
+func (s *VariationService) WaitForVariation(ctx context.Context, id string, interval time.Duration) (resp *GetVariationResponse, err error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ctx, span := s.client.startSpan(ctx, "Variation.WaitForVariation", Attr("leonardo.id", id))
+	polls := 0
+	defer func() {
+		span.SetAttributes(Attr("leonardo.polls", polls))
+		endSpan(span, err)
+	}()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-timer.C:
+		}
+
+		polls++
+		resp, err = s.GetVariation(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if v := resp.Variation(); v != nil && v.Status != nil && *v.Status != GenerationStatusPending {
+			span.SetAttributes(Attr("leonardo.status", string(*v.Status)))
+			if *v.Status == GenerationStatusFailed {
+				return resp, fmt.Errorf("variation %s failed", id)
+			}
+			return resp, nil
+		}
+		timer.Reset(interval)
+	}
+}
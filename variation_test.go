@@ -102,10 +102,7 @@ func TestCreateUpscaleVariation(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := UpscaleVariationResponse{
-			SdUpscaleJob: struct {
-				ID            *string `json:"id"`
-				APICreditCost *int    `json:"apiCreditCost"`
-			}{
+			SdUpscaleJob: VariationJob{
 				ID:            Ptr("upscale-job-001"),
 				APICreditCost: Ptr(8),
 			},
@@ -282,18 +279,12 @@ func TestGetVariation(t *testing.T) {
 		// Mock successful response
 		w.Header().Set("Content-Type", "application/json")
 		response := GetVariationResponse{
-			GeneratedImageVariationGeneric: []struct {
-				CreatedAt     *Time   `json:"createdAt"`
-				ID            *string `json:"id"`
-				Status        *string `json:"status"`
-				TransformType *string `json:"transformType"`
-				URL           *string `json:"url"`
-			}{
+			GeneratedImageVariationGeneric: []Variation{
 				{
-					CreatedAt:     &Time{time.Now().Add(-1 * time.Hour)},
+					CreatedAt:     &Time{Time: time.Now().Add(-1 * time.Hour)},
 					ID:            Ptr("var-001"),
-					Status:        Ptr("COMPLETE"),
-					TransformType: Ptr("UPSCALE"),
+					Status:        Ptr(GenerationStatusComplete),
+					TransformType: Ptr(TransformTypeUpscale),
 					URL:           Ptr("https://example.com/upscaled-var-001.jpg"),
 				},
 			},
@@ -0,0 +1,431 @@
+package leonardo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// JobKind identifies how a watched job is polled.
+type JobKind string
+
+const (
+	JobGeneration JobKind = "generation"
+	JobTexture    JobKind = "texture"
+	JobVariation  JobKind = "variation"
+	JobTraining   JobKind = "training"
+)
+
+// JobEventType identifies the kind of a JobEvent.
+type JobEventType string
+
+const (
+	// JobQueued is emitted when a job starts being watched.
+	JobQueued JobEventType = "queued"
+	// JobStatusChanged is emitted when a job moves to another non-final status.
+	JobStatusChanged JobEventType = "status_changed"
+	// JobCompleted is emitted, with the job's outputs, when a job completes.
+	JobCompleted JobEventType = "completed"
+	// JobFailed is emitted when a job fails.
+	JobFailed JobEventType = "failed"
+	// JobPollError is emitted when polling a job fails. The job stays watched.
+	JobPollError JobEventType = "poll_error"
+)
+
+// JobEvent reports progress of a watched job.
+type JobEvent struct {
+	Type   JobEventType
+	Kind   JobKind
+	ID     string
+	Status GenerationStatus
+
+	// Images holds the outputs of a completed generation or texture.
+	Images []GeneratedImage
+	// Variation holds a completed variation.
+	Variation *Variation
+	// Model holds a completed custom model.
+	Model *CustomModel
+
+	// Err is the polling error of a JobPollError event.
+	Err error
+}
+
+// Backpressure decides what a subscription does when its channel is full.
+type Backpressure int
+
+const (
+	// BackpressureBlock holds up polling until the subscriber catches up.
+	BackpressureBlock Backpressure = iota
+	// BackpressureDropOldest discards the oldest undelivered event to make room.
+	BackpressureDropOldest
+)
+
+// DefaultWatchBatchSize is the page size the watcher uses when listing generations.
+const DefaultWatchBatchSize = 50
+
+// Watcher polls many jobs together and emits a JobEvent whenever one changes.
+// Generations and textures are polled in batches by listing the user's recent
+// generations; anything not found there, and all variations and training jobs,
+// are polled individually. Jobs are unwatched once they complete or fail.
+type Watcher struct {
+	client *Client
+
+	// Interval is the time between polling rounds. Zero uses DefaultPollInterval.
+	Interval time.Duration
+
+	// UserID is the user whose generations are listed. If empty it is looked up
+	// with GetUserInfo on the first round.
+	UserID string
+
+	// BatchSize is the page size used to list generations, and MaxPages how
+	// many pages are read per round. Zero uses DefaultWatchBatchSize and 2 pages.
+	BatchSize int
+	MaxPages  int
+
+	mu   sync.Mutex
+	jobs map[string]*watchedJob
+	subs map[*Subscription]struct{}
+}
+
+// watchedJob is a job tracked by a Watcher.
+type watchedJob struct {
+	kind   JobKind
+	status GenerationStatus
+}
+
+// NewWatcher creates a Watcher. Call Run to start polling.
+func (c *Client) NewWatcher() *Watcher {
+	return &Watcher{
+		client: c,
+		jobs:   map[string]*watchedJob{},
+		subs:   map[*Subscription]struct{}{},
+	}
+}
+
+// Watch starts watching the job with the given ID and emits JobQueued.
+// Watching a job that is already watched does nothing.
+func (w *Watcher) Watch(ctx context.Context, kind JobKind, id string) {
+	w.mu.Lock()
+	if _, ok := w.jobs[id]; ok {
+		w.mu.Unlock()
+		return
+	}
+	w.jobs[id] = &watchedJob{kind: kind, status: GenerationStatusPending}
+	w.mu.Unlock()
+
+	w.emit(ctx, JobEvent{Type: JobQueued, Kind: kind, ID: id, Status: GenerationStatusPending})
+}
+
+// Unwatch stops watching the job with the given ID.
+func (w *Watcher) Unwatch(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.jobs, id)
+}
+
+// Len returns the number of jobs being watched.
+func (w *Watcher) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.jobs)
+}
+
+// Run polls the watched jobs every Interval until ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		w.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll runs a single polling round.
+func (w *Watcher) Poll(ctx context.Context) {
+	w.mu.Lock()
+	jobs := make(map[string]JobKind, len(w.jobs))
+	batched := 0
+	for id, job := range w.jobs {
+		jobs[id] = job.kind
+		if job.kind == JobGeneration || job.kind == JobTexture {
+			batched++
+		}
+	}
+	w.mu.Unlock()
+
+	if batched > 0 {
+		for _, gen := range w.listGenerations(ctx, jobs) {
+			id := deref(gen.ID)
+			w.update(ctx, jobs[id], id, deref(gen.Status), JobEvent{Images: gen.GeneratedImages})
+			delete(jobs, id)
+		}
+	}
+
+	for id, kind := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		w.pollJob(ctx, kind, id)
+	}
+}
+
+// listGenerations lists the user's recent generations and returns the watched
+// ones it finds. Errors are ignored; the jobs are then polled individually.
+func (w *Watcher) listGenerations(ctx context.Context, jobs map[string]JobKind) []Generation {
+	userID, err := w.userID(ctx)
+	if err != nil {
+		return nil
+	}
+	limit, pages := w.BatchSize, w.MaxPages
+	if limit <= 0 {
+		limit = DefaultWatchBatchSize
+	}
+	if pages <= 0 {
+		pages = 2
+	}
+
+	wanted := 0
+	for _, kind := range jobs {
+		if kind == JobGeneration || kind == JobTexture {
+			wanted++
+		}
+	}
+
+	var found []Generation
+	for page := 0; page < pages && len(found) < wanted; page++ {
+		resp, err := w.client.Images.GetGenerationsByUserID(ctx, userID, limit, page*limit)
+		if err != nil {
+			return found
+		}
+		for _, gen := range resp.Generations {
+			if kind, ok := jobs[deref(gen.ID)]; ok && gen.Status != nil && (kind == JobGeneration || kind == JobTexture) {
+				found = append(found, gen)
+			}
+		}
+		if len(resp.Generations) < limit {
+			break
+		}
+	}
+	return found
+}
+
+// userID returns UserID, looking it up the first time.
+func (w *Watcher) userID(ctx context.Context) (string, error) {
+	w.mu.Lock()
+	userID := w.UserID
+	w.mu.Unlock()
+	if userID != "" {
+		return userID, nil
+	}
+
+	resp, err := w.client.User.GetUserInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	if details := resp.Details(); details != nil {
+		userID = deref(details.User.ID)
+	}
+	if userID == "" {
+		return "", errors.New("user info has no user ID")
+	}
+	w.mu.Lock()
+	w.UserID = userID
+	w.mu.Unlock()
+	return userID, nil
+}
+
+// pollJob polls a single job.
+func (w *Watcher) pollJob(ctx context.Context, kind JobKind, id string) {
+	var status GenerationStatus
+	var event JobEvent
+	switch kind {
+	case JobVariation:
+		resp, err := w.client.Variation.GetVariation(ctx, id)
+		if err != nil {
+			w.emit(ctx, JobEvent{Type: JobPollError, Kind: kind, ID: id, Err: err})
+			return
+		}
+		if v := resp.Variation(); v != nil {
+			status, event.Variation = deref(v.Status), v
+		}
+	case JobTraining:
+		resp, err := w.client.Models.GetCustomModel(ctx, id)
+		if err != nil {
+			w.emit(ctx, JobEvent{Type: JobPollError, Kind: kind, ID: id, Err: err})
+			return
+		}
+		status, event.Model = GenerationStatus(deref(resp.CustomModelsByPK.Status)), &resp.CustomModelsByPK
+	default:
+		resp, err := w.client.Images.GetImageGeneration(ctx, id)
+		if err != nil {
+			w.emit(ctx, JobEvent{Type: JobPollError, Kind: kind, ID: id, Err: err})
+			return
+		}
+		status, event.Images = resp.Status(), resp.Images()
+	}
+	w.update(ctx, kind, id, status, event)
+}
+
+// update records a job's status and emits an event if it changed. The job is
+// unwatched once it completes or fails.
+func (w *Watcher) update(ctx context.Context, kind JobKind, id string, status GenerationStatus, event JobEvent) {
+	if status == "" {
+		return
+	}
+
+	w.mu.Lock()
+	job, ok := w.jobs[id]
+	if !ok || job.status == status {
+		w.mu.Unlock()
+		return
+	}
+	job.status = status
+	if status == GenerationStatusComplete || status == GenerationStatusFailed {
+		delete(w.jobs, id)
+	}
+	w.mu.Unlock()
+
+	event.Kind, event.ID, event.Status = kind, id, status
+	switch status {
+	case GenerationStatusComplete:
+		event.Type = JobCompleted
+	case GenerationStatusFailed:
+		event.Type = JobFailed
+		event.Images, event.Variation, event.Model = nil, nil, nil
+	default:
+		event.Type = JobStatusChanged
+		event.Images, event.Variation, event.Model = nil, nil, nil
+	}
+	w.emit(ctx, event)
+}
+
+// Subscription receives the events of a Watcher.
+type Subscription struct {
+	watcher *Watcher
+	ch      chan JobEvent
+	fn      func(JobEvent)
+	policy  Backpressure
+
+	mu      sync.Mutex
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+// Subscribe returns a subscription delivering events on a channel with the
+// given buffer size. policy decides what happens when the buffer is full; an
+// unbuffered subscription always blocks.
+func (w *Watcher) Subscribe(buffer int, policy Backpressure) *Subscription {
+	sub := &Subscription{watcher: w, ch: make(chan JobEvent, buffer), policy: policy, done: make(chan struct{})}
+	w.addSubscription(sub)
+	return sub
+}
+
+// SubscribeFunc returns a subscription that calls fn for every event. fn is
+// called from the polling goroutine, so a slow fn holds up polling.
+func (w *Watcher) SubscribeFunc(fn func(JobEvent)) *Subscription {
+	sub := &Subscription{watcher: w, fn: fn, done: make(chan struct{})}
+	w.addSubscription(sub)
+	return sub
+}
+
+// addSubscription registers sub.
+func (w *Watcher) addSubscription(sub *Subscription) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs[sub] = struct{}{}
+}
+
+// Events returns the channel events are delivered on. It is nil for
+// subscriptions made with SubscribeFunc, and closed by Unsubscribe.
+func (s *Subscription) Events() <-chan JobEvent {
+	return s.ch
+}
+
+// Dropped returns the number of events discarded under BackpressureDropOldest.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe stops delivery and closes the events channel.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.watcher.mu.Lock()
+		delete(s.watcher.subs, s)
+		s.watcher.mu.Unlock()
+
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.ch != nil {
+			close(s.ch)
+		}
+	})
+}
+
+// emit delivers event to every subscription.
+func (w *Watcher) emit(ctx context.Context, event JobEvent) {
+	w.mu.Lock()
+	subs := make([]*Subscription, 0, len(w.subs))
+	for sub := range w.subs {
+		subs = append(subs, sub)
+	}
+	w.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.deliver(ctx, event)
+	}
+}
+
+// deliver passes event to the subscriber according to its backpressure policy.
+func (s *Subscription) deliver(ctx context.Context, event JobEvent) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	if s.fn != nil {
+		s.fn(event)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	if s.policy == BackpressureDropOldest && cap(s.ch) > 0 {
+		for {
+			select {
+			case s.ch <- event:
+				return
+			default:
+			}
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	}
+
+	select {
+	case s.ch <- event:
+	case <-s.done:
+	case <-ctx.Done():
+	}
+}
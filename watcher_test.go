@@ -0,0 +1,146 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestWatcher tests batching, per-job polling and the events emitted.
+func TestWatcher(t *testing.T) {
+	var mu sync.Mutex
+	statuses := map[string]GenerationStatus{
+		"gen-1": GenerationStatusPending,
+		"gen-2": GenerationStatusPending,
+		"var-1": GenerationStatusPending,
+		"mod-1": "TRAINING",
+	}
+	requests := map[string]int{}
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path]++
+
+		switch {
+		case r.URL.Path == "/me":
+			json.NewEncoder(w).Encode(GetUserInfoResponse{UserDetails: []UserDetails{{User: User{ID: Ptr("user-1")}}}})
+		case r.URL.Path == "/generations/user/user-1":
+			gens := []Generation{{ID: Ptr("other"), Status: Ptr(GenerationStatusComplete)}}
+			for _, id := range []string{"gen-1", "gen-2"} {
+				gen := Generation{ID: Ptr(id), Status: Ptr(statuses[id])}
+				if statuses[id] == GenerationStatusComplete {
+					gen.GeneratedImages = []GeneratedImage{{ID: Ptr("img-" + id)}}
+				}
+				gens = append(gens, gen)
+			}
+			json.NewEncoder(w).Encode(GetGenerationsByUserResponse{Generations: gens})
+		case r.URL.Path == "/generations/gen-old":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(APIErrorResponse{Code: "not-found", Message: "Generation not found."})
+		case strings.HasPrefix(r.URL.Path, "/variations/"):
+			id := strings.TrimPrefix(r.URL.Path, "/variations/")
+			json.NewEncoder(w).Encode(GetVariationResponse{GeneratedImageVariationGeneric: []Variation{{ID: Ptr(id), Status: Ptr(statuses[id])}}})
+		case strings.HasPrefix(r.URL.Path, "/models/"):
+			id := strings.TrimPrefix(r.URL.Path, "/models/")
+			json.NewEncoder(w).Encode(GetCustomModelResponse{CustomModelsByPK: CustomModel{ID: Ptr(id), Status: Ptr(string(statuses[id]))}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+	client.Variation = client.NewVariationService()
+	client.Models = client.NewModelsService()
+	client.User = client.NewUserService()
+
+	ctx := context.Background()
+	watcher := client.NewWatcher()
+	sub := watcher.Subscribe(16, BackpressureBlock)
+	var callbacks []JobEvent
+	watcher.SubscribeFunc(func(e JobEvent) { callbacks = append(callbacks, e) })
+
+	watcher.Watch(ctx, JobGeneration, "gen-1")
+	watcher.Watch(ctx, JobTexture, "gen-2")
+	watcher.Watch(ctx, JobGeneration, "gen-old")
+	watcher.Watch(ctx, JobVariation, "var-1")
+	watcher.Watch(ctx, JobTraining, "mod-1")
+	watcher.Watch(ctx, JobTraining, "mod-1")
+
+	watcher.Poll(ctx)
+	mu.Lock()
+	statuses["gen-1"] = GenerationStatusComplete
+	statuses["gen-2"] = GenerationStatusFailed
+	statuses["var-1"] = GenerationStatusComplete
+	statuses["mod-1"] = GenerationStatusComplete
+	mu.Unlock()
+	watcher.Poll(ctx)
+	watcher.Unwatch("gen-old")
+	sub.Unsubscribe()
+
+	events := map[string][]JobEvent{}
+	for e := range sub.Events() {
+		events[e.ID] = append(events[e.ID], e)
+	}
+	if len(callbacks) != 12 {
+		t.Errorf("Expected 12 events, got %d: %+v", len(callbacks), callbacks)
+	}
+
+	if e := events["gen-1"]; len(e) != 2 || e[0].Type != JobQueued || e[1].Type != JobCompleted || len(e[1].Images) != 1 {
+		t.Errorf("Unexpected gen-1 events: %+v", e)
+	}
+	if e := events["gen-2"]; len(e) != 2 || e[1].Type != JobFailed || e[1].Kind != JobTexture {
+		t.Errorf("Unexpected gen-2 events: %+v", e)
+	}
+	if e := events["gen-old"]; len(e) != 3 || e[1].Type != JobPollError || e[1].Err == nil {
+		t.Errorf("Unexpected gen-old events: %+v", e)
+	}
+	if e := events["var-1"]; len(e) != 2 || e[1].Type != JobCompleted || *e[1].Variation.ID != "var-1" {
+		t.Errorf("Unexpected var-1 events: %+v", e)
+	}
+	if e := events["mod-1"]; len(e) != 3 || e[1].Type != JobStatusChanged || e[1].Status != "TRAINING" || e[2].Model == nil {
+		t.Errorf("Unexpected mod-1 events: %+v", e)
+	}
+
+	if watcher.Len() != 0 {
+		t.Errorf("Expected finished jobs to be unwatched, got %d", watcher.Len())
+	}
+	if requests["/me"] != 1 || requests["/generations/user/user-1"] != 2 || requests["/generations/gen-1"] != 0 {
+		t.Errorf("Expected generations to be batched, got %v", requests)
+	}
+}
+
+// TestWatcherBackpressure tests dropping the oldest events for a slow subscriber.
+func TestWatcherBackpressure(t *testing.T) {
+	watcher := NewClient("test-api-key").NewWatcher()
+	sub := watcher.Subscribe(2, BackpressureDropOldest)
+
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		watcher.Watch(ctx, JobGeneration, id)
+	}
+	if sub.Dropped() != 2 {
+		t.Errorf("Expected 2 dropped events, got %d", sub.Dropped())
+	}
+	if e := <-sub.Events(); e.ID != "c" {
+		t.Errorf("Expected the oldest events to be dropped, got %s", e.ID)
+	}
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	watcher.Watch(ctx, JobGeneration, "e")
+}
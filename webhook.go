@@ -0,0 +1,342 @@
+package leonardo
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookEventType identifies the kind of a webhook callback.
+type WebhookEventType string
+
+const (
+	WebhookEventImageGenerationComplete  WebhookEventType = "image_generation.complete"
+	WebhookEventImageVariationComplete   WebhookEventType = "image_variation.complete"
+	WebhookEventMotionGenerationComplete WebhookEventType = "motion_generation.complete"
+)
+
+// WebhookEvent is a callback delivered by Leonardo to the webhook URL of an API key.
+type WebhookEvent struct {
+	Type       WebhookEventType `json:"type"`
+	Object     string           `json:"object"`
+	Timestamp  int64            `json:"timestamp"`
+	APIVersion string           `json:"api_version"`
+	Data       struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+
+	id string
+}
+
+// ID returns the ID of the generation or variation the event is about.
+func (e *WebhookEvent) ID() string {
+	return e.id
+}
+
+// Generation decodes the generation carried by an image or motion generation event.
+func (e *WebhookEvent) Generation() (*GenerationDetails, error) {
+	var obj struct {
+		GenerationDetails
+		Images []GeneratedImage `json:"images"`
+	}
+	if err := json.Unmarshal(e.Data.Object, &obj); err != nil {
+		return nil, fmt.Errorf("decoding webhook generation failed: %w", err)
+	}
+	if obj.GeneratedImages == nil {
+		obj.GeneratedImages = obj.Images
+	}
+	return &obj.GenerationDetails, nil
+}
+
+// Variation decodes the variation carried by an image variation event.
+func (e *WebhookEvent) Variation() (*Variation, error) {
+	var v Variation
+	if err := json.Unmarshal(e.Data.Object, &v); err != nil {
+		return nil, fmt.Errorf("decoding webhook variation failed: %w", err)
+	}
+	return &v, nil
+}
+
+// WebhookHandlerFunc handles a webhook event.
+type WebhookHandlerFunc func(ctx context.Context, event *WebhookEvent)
+
+// DefaultWebhookTimeout is how long the webhook waits for a callback before
+// falling back to polling when no timeout is given.
+const DefaultWebhookTimeout = 2 * time.Minute
+
+// Webhook is an http.Handler that receives Leonardo webhook callbacks. It
+// verifies the callback API key, drops redeliveries, and passes each event to
+// the registered handlers and to any pending Wait calls for the same job.
+type Webhook struct {
+	client         *Client
+	callbackAPIKey string
+
+	// Timeout is how long the Wait methods wait for a callback before polling
+	// the API instead. Zero uses DefaultWebhookTimeout.
+	Timeout time.Duration
+
+	// PollInterval is the interval used when falling back to polling.
+	PollInterval time.Duration
+
+	// DedupeWindow is how long delivered events are remembered, both to drop
+	// redeliveries and to resolve Wait calls made after the callback arrived.
+	// Zero uses one hour.
+	DedupeWindow time.Duration
+
+	mu       sync.Mutex
+	handlers map[WebhookEventType][]WebhookHandlerFunc
+	waiters  map[string][]chan *WebhookEvent
+	seen     map[string]*deliveredEvent
+}
+
+// deliveredEvent is an event remembered for deduplication.
+type deliveredEvent struct {
+	event *WebhookEvent
+	at    time.Time
+}
+
+// NewWebhook creates a Webhook that accepts callbacks carrying callbackAPIKey,
+// the callback API key configured alongside the webhook URL. If callbackAPIKey
+// is empty every callback is rejected.
+func (c *Client) NewWebhook(callbackAPIKey string) *Webhook {
+	return &Webhook{
+		client:         c,
+		callbackAPIKey: callbackAPIKey,
+		handlers:       map[WebhookEventType][]WebhookHandlerFunc{},
+		waiters:        map[string][]chan *WebhookEvent{},
+		seen:           map[string]*deliveredEvent{},
+	}
+}
+
+// Handle registers fn for events of type t. An empty type receives every event.
+func (h *Webhook) Handle(t WebhookEventType, fn WebhookHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[t] = append(h.handlers[t], fn)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := decodeWebhookEvent(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.deliver(event) {
+		h.dispatch(r.Context(), event)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorized reports whether r carries the callback API key.
+func (h *Webhook) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || h.callbackAPIKey == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.callbackAPIKey)) == 1
+}
+
+// decodeWebhookEvent decodes a callback body.
+func decodeWebhookEvent(r io.Reader) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.NewDecoder(io.LimitReader(r, 10<<20)).Decode(&event); err != nil {
+		return nil, fmt.Errorf("decoding webhook event failed: %w", err)
+	}
+	var obj struct {
+		ID string `json:"id"`
+	}
+	if len(event.Data.Object) > 0 {
+		if err := json.Unmarshal(event.Data.Object, &obj); err != nil {
+			return nil, fmt.Errorf("decoding webhook event failed: %w", err)
+		}
+	}
+	if event.Type == "" || obj.ID == "" {
+		return nil, errors.New("decoding webhook event failed: missing type or object id")
+	}
+	event.id = obj.ID
+	return &event, nil
+}
+
+// deliver records event and resolves its waiters. It reports false if the
+// event is a redelivery.
+func (h *Webhook) deliver(event *WebhookEvent) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	window := h.DedupeWindow
+	if window <= 0 {
+		window = time.Hour
+	}
+	for key, d := range h.seen {
+		if now.Sub(d.at) > window {
+			delete(h.seen, key)
+		}
+	}
+
+	key := string(event.Type) + ":" + event.id
+	if _, ok := h.seen[key]; ok {
+		return false
+	}
+	h.seen[key] = &deliveredEvent{event: event, at: now}
+
+	for _, ch := range h.waiters[event.id] {
+		ch <- event
+	}
+	delete(h.waiters, event.id)
+	return true
+}
+
+// dispatch passes event to the handlers registered for its type.
+func (h *Webhook) dispatch(ctx context.Context, event *WebhookEvent) {
+	h.mu.Lock()
+	handlers := append(append([]WebhookHandlerFunc(nil), h.handlers[event.Type]...), h.handlers[""]...)
+	h.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(ctx, event)
+	}
+}
+
+// Wait waits for a callback about the job with the given ID and returns it. It
+// returns immediately if the callback already arrived within DedupeWindow.
+// Unlike the typed Wait methods it does not fall back to polling; bound it with ctx.
+func (h *Webhook) Wait(ctx context.Context, id string) (*WebhookEvent, error) {
+	ch, ok := h.subscribe(id)
+	if !ok {
+		return <-ch, nil
+	}
+	select {
+	case event := <-ch:
+		return event, nil
+	case <-ctx.Done():
+		h.unsubscribe(id, ch)
+		return nil, ctx.Err()
+	}
+}
+
+// subscribe registers a waiter for id. It reports false, with the event already
+// in the channel, if a callback for id has been delivered.
+func (h *Webhook) subscribe(id string) (chan *WebhookEvent, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan *WebhookEvent, 1)
+	for _, d := range h.seen {
+		if d.event.id == id {
+			ch <- d.event
+			return ch, false
+		}
+	}
+	h.waiters[id] = append(h.waiters[id], ch)
+	return ch, true
+}
+
+// unsubscribe removes a waiter registered by subscribe.
+func (h *Webhook) unsubscribe(id string, ch chan *WebhookEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	waiters := h.waiters[id]
+	for i, c := range waiters {
+		if c == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(h.waiters, id)
+	} else {
+		h.waiters[id] = waiters
+	}
+}
+
+// waitTimeout waits for a callback about id for at most Timeout. It returns a
+// nil event, and no error, when the timeout passes first.
+func (h *Webhook) waitTimeout(ctx context.Context, id string) (*WebhookEvent, error) {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	event, err := h.Wait(waitCtx, id)
+	if err != nil && ctx.Err() == nil {
+		return nil, nil
+	}
+	return event, err
+}
+
+// WaitForGeneration waits for the completion callback of an image or motion
+// generation. If none arrives within Timeout it polls with
+// ImagesService.WaitForGeneration instead. A FAILED generation is returned with an error.
+func (h *Webhook) WaitForGeneration(ctx context.Context, id string) (*GetGenerationResponse, error) {
+	event, err := h.waitTimeout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return h.client.Images.WaitForGeneration(ctx, id, h.PollInterval)
+	}
+
+	gen, err := event.Generation()
+	if err != nil {
+		return nil, err
+	}
+	resp := &GetGenerationResponse{GenerationsByPK: *gen}
+	if resp.Status() == GenerationStatusFailed {
+		return resp, fmt.Errorf("generation %s failed", id)
+	}
+	return resp, nil
+}
+
+// WaitForVariation waits for the completion callback of a variation. If none
+// arrives within Timeout it polls with VariationService.WaitForVariation
+// instead. A FAILED variation is returned with an error.
+func (h *Webhook) WaitForVariation(ctx context.Context, id string) (*Variation, error) {
+	event, err := h.waitTimeout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if event == nil {
+		resp, err := h.client.Variation.WaitForVariation(ctx, id, h.PollInterval)
+		if resp == nil {
+			return nil, err
+		}
+		v := resp.Variation()
+		if err != nil && (v == nil || deref(v.Status) != GenerationStatusFailed) {
+			return nil, err
+		}
+		return v, err
+	}
+
+	v, err := event.Variation()
+	if err != nil {
+		return nil, err
+	}
+	if deref(v.Status) == GenerationStatusFailed {
+		return v, fmt.Errorf("variation %s failed", id)
+	}
+	return v, nil
+}
@@ -0,0 +1,212 @@
+package leonardo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testGenerationCallback = `{
+	"type": "image_generation.complete",
+	"object": "generation",
+	"timestamp": 1705051402,
+	"api_version": "v1",
+	"data": {
+		"object": {
+			"id": "gen-123",
+			"status": "COMPLETE",
+			"prompt": "A lighthouse.",
+			"imageWidth": 1024,
+			"imageHeight": 768,
+			"images": [{"id": "img-1", "url": "https://cdn.leonardo.ai/img-1.jpg"}]
+		}
+	}
+}`
+
+// deliverCallback posts body to h and returns the response status.
+func deliverCallback(h http.Handler, apiKey, body string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// TestWebhook tests verifying, decoding, deduplicating and dispatching callbacks.
+func TestWebhook(t *testing.T) {
+	client := NewClient("test-api-key")
+	webhook := client.NewWebhook("callback-key")
+
+	var events []*WebhookEvent
+	webhook.Handle(WebhookEventImageGenerationComplete, func(ctx context.Context, event *WebhookEvent) {
+		events = append(events, event)
+	})
+
+	if code := deliverCallback(webhook, "wrong-key", testGenerationCallback); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for the wrong key, got %d", code)
+	}
+	if code := deliverCallback(webhook, "callback-key", `{"type": "image_generation.complete"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a payload without an object, got %d", code)
+	}
+	for i := 0; i < 2; i++ {
+		if code := deliverCallback(webhook, "callback-key", testGenerationCallback); code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", code)
+		}
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("Expected the redelivery to be dropped, got %d events", len(events))
+	}
+	if events[0].ID() != "gen-123" {
+		t.Errorf("Expected event for gen-123, got %s", events[0].ID())
+	}
+	gen, err := events[0].Generation()
+	if err != nil {
+		t.Fatalf("Generation failed: %v", err)
+	}
+	if *gen.Status != GenerationStatusComplete || *gen.Width != 1024 || len(gen.GeneratedImages) != 1 || *gen.GeneratedImages[0].ID != "img-1" {
+		t.Errorf("Unexpected generation: %+v", gen)
+	}
+
+	// A callback delivered before the wait still resolves it.
+	resp, err := webhook.WaitForGeneration(context.Background(), "gen-123")
+	if err != nil {
+		t.Fatalf("WaitForGeneration failed: %v", err)
+	}
+	if *resp.GenerationsByPK.ID != "gen-123" || len(resp.Images()) != 1 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+// TestWebhookWaitForVariation tests resolving a pending wait from a callback.
+func TestWebhookWaitForVariation(t *testing.T) {
+	client := NewClient("test-api-key")
+	webhook := client.NewWebhook("callback-key")
+
+	done := make(chan *Variation)
+	go func() {
+		v, err := webhook.WaitForVariation(context.Background(), "var-1")
+		if err != nil {
+			t.Errorf("WaitForVariation failed: %v", err)
+		}
+		done <- v
+	}()
+
+	// Deliver once the wait is registered.
+	for {
+		webhook.mu.Lock()
+		n := len(webhook.waiters["var-1"])
+		webhook.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	body := `{"type": "image_variation.complete", "object": "variation", "data": {"object": {"id": "var-1", "status": "COMPLETE", "transformType": "UPSCALE", "url": "https://cdn.leonardo.ai/var-1.jpg"}}}`
+	if code := deliverCallback(webhook, "callback-key", body); code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", code)
+	}
+
+	v := <-done
+	if v == nil || *v.TransformType != TransformTypeUpscale || *v.URL != "https://cdn.leonardo.ai/var-1.jpg" {
+		t.Errorf("Unexpected variation: %+v", v)
+	}
+}
+
+// TestWebhookFallback tests polling when no callback arrives in time.
+func TestWebhookFallback(t *testing.T) {
+	polls := 0
+
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generations/gen-123" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		polls++
+		status := GenerationStatusPending
+		if polls > 1 {
+			status = GenerationStatusFailed
+		}
+		json.NewEncoder(w).Encode(GetGenerationResponse{GenerationsByPK: GenerationDetails{ID: Ptr("gen-123"), Status: &status}})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Images = client.NewImagesService()
+
+	webhook := client.NewWebhook("callback-key")
+	webhook.Timeout = 10 * time.Millisecond
+	webhook.PollInterval = time.Millisecond
+
+	resp, err := webhook.WaitForGeneration(context.Background(), "gen-123")
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("Expected the failed generation to be reported, got %v", err)
+	}
+	if polls != 2 || resp.Status() != GenerationStatusFailed {
+		t.Errorf("Expected 2 polls ending in FAILED, got %d polls and %+v", polls, resp)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := webhook.WaitForGeneration(ctx, "gen-456"); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(webhook.waiters) != 0 {
+		t.Errorf("Expected the waiters to be cleaned up, got %d", len(webhook.waiters))
+	}
+}
+
+// TestWebhookEmptyKey tests that a webhook without a callback API key rejects every callback.
+func TestWebhookEmptyKey(t *testing.T) {
+	client := NewClient("test-api-key")
+	webhook := client.NewWebhook("")
+
+	if code := deliverCallback(webhook, "", testGenerationCallback); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for an empty key, got %d", code)
+	}
+}
+
+// TestWebhookVariationFallback tests that a FAILED variation found by polling is returned with its error.
+func TestWebhookVariationFallback(t *testing.T) {
+	// Mock server setup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/variations/var-1" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(GetVariationResponse{GeneratedImageVariationGeneric: []Variation{{ID: Ptr("var-1"), Status: Ptr(GenerationStatusFailed)}}})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Initialize client with mock server URL
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		APIKey:     "test-api-key",
+	}
+	client.Variation = client.NewVariationService()
+
+	webhook := client.NewWebhook("callback-key")
+	webhook.Timeout = 10 * time.Millisecond
+	webhook.PollInterval = time.Millisecond
+
+	v, err := webhook.WaitForVariation(context.Background(), "var-1")
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("Expected the failed variation to be reported, got %v", err)
+	}
+	if v == nil || deref(v.Status) != GenerationStatusFailed {
+		t.Errorf("Expected the FAILED variation, got %+v", v)
+	}
+}